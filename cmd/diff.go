@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/services"
+	"github.com/mona-actions/gh-gitlab-stats/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var diffOutputFile string
+
+// diffCmd compares two reports produced by earlier scans
+var diffCmd = &cobra.Command{
+	Use:   "diff <old-report> <new-report>",
+	Short: "Compare two scan reports",
+	Long: `Compare two reports written by earlier scans (CSV, JSON or NDJSON) and list
+the projects that were added, removed, renamed or changed in between: size
+growth, new commits, branches and tags, and newly archived projects.
+
+Projects are matched by project ID, else by URL. A summary
+is printed to the console and one row per changed project is written to a CSV file.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffOutputFile, "output-file", "", "Path of the diff CSV, or \"-\" to write it to stdout (the summary then goes to stderr). Defaults to a timestamped file")
+	rootCmd.AddCommand(diffCmd)
+}
+
+// runDiff compares the two reports given as arguments
+func runDiff(cmd *cobra.Command, args []string) error {
+	if diffOutputFile == "" {
+		diffOutputFile = fmt.Sprintf("gitlab-stats-diff-%s.csv", time.Now().Format("2006-01-02-15-04-05"))
+	}
+	if diffOutputFile == "-" {
+		ui.Console = os.Stderr
+	}
+
+	oldReport, err := ui.ReadReport(args[0])
+	if err != nil {
+		return err
+	}
+	newReport, err := ui.ReadReport(args[1])
+	if err != nil {
+		return err
+	}
+	if oldReport.Format == "csv" || newReport.Format == "csv" {
+		fmt.Fprintln(ui.Console, "Note: CSV reports round sizes to whole MB, so sizes are compared in whole MB")
+	}
+	for i, report := range []*ui.Report{oldReport, newReport} {
+		if missingProjectIDs(report) {
+			fmt.Fprintf(ui.Console, "Note: %s has no project IDs (written by an older version), so renamed projects are listed as removed and added\n", args[i])
+		}
+	}
+
+	diff := services.DiffReports(oldReport, newReport)
+	if err := ui.WriteDiffCSV(diff, diffOutputFile); err != nil {
+		return err
+	}
+
+	ui.PrintDiffSummary(ui.Console, diff, args[0], args[1])
+	fmt.Fprintf(ui.Console, "Diff written to: %s\n", describeOutput(diffOutputFile))
+	return nil
+}
+
+// missingProjectIDs reports whether a report has rows without a project ID, as
+// CSV reports written before the Project_ID column was added do
+func missingProjectIDs(report *ui.Report) bool {
+	for _, stat := range report.Stats {
+		if stat.ProjectID == 0 {
+			return true
+		}
+	}
+	return false
+}
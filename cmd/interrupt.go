@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/api"
+	"github.com/mona-actions/gh-gitlab-stats/internal/services"
+	"github.com/mona-actions/gh-gitlab-stats/internal/ui"
+)
+
+// errInterrupted is the cause of the interrupt context
+var errInterrupted = errors.New("scan interrupted")
+
+// handleInterrupts stops the scan gracefully on the first SIGINT/SIGTERM, letting
+// in-flight projects finish so partial results can be written, and exits
+// immediately on the second. Requests made with the returned context stop waiting
+// to be retried on interrupt, so in-flight projects don't wait out long backoffs.
+// The returned function stops listening for signals.
+func handleInterrupts(ctx context.Context, scanner *services.Scanner, checkpoint *services.Checkpoint) (context.Context, func()) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	interrupted, interrupt := context.WithCancelCause(context.Background())
+
+	go func() {
+		select {
+		case <-signals:
+		case <-done:
+			return
+		}
+
+		fmt.Fprintf(ui.Console, "\n\n⚠ Interrupt received: finishing projects in progress before writing partial results.\n")
+		fmt.Fprintf(ui.Console, "  Press Ctrl-C again to exit immediately.\n")
+		interrupt(errInterrupted)
+		scanner.Interrupt()
+
+		select {
+		case <-signals:
+			fmt.Fprintf(ui.Console, "\nForced exit. Completed projects are saved in checkpoint: %s\n", checkpoint.Path())
+			fmt.Fprintf(ui.Console, "Resume the scan with: --resume %s\n", checkpoint.Path())
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	return api.WithRetryInterrupt(ctx, interrupted), func() {
+		signal.Stop(signals)
+		close(done)
+		interrupt(nil)
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+	"github.com/mona-actions/gh-gitlab-stats/internal/services"
+	"github.com/mona-actions/gh-gitlab-stats/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mergeOutput     string
+	mergeOutputFile string
+)
+
+// mergeCmd combines the reports of several scans into one
+var mergeCmd = &cobra.Command{
+	Use:   "merge <report>...",
+	Short: "Merge several scan reports into one",
+	Long: `Merge reports written by earlier scans (CSV, JSON or NDJSON, in any combination)
+into one consolidated report, for example the reports of separate per-namespace runs.
+
+Projects found in several reports, or several times in one report, are listed once
+with the statistics of the newest scan. Projects are matched by project ID, else by
+URL. A single report can be given to drop its duplicate rows.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMerge,
+}
+
+func init() {
+	mergeCmd.Flags().StringVarP(&mergeOutput, "output", "O", "csv", "Output format: \"csv\", \"json\" or \"ndjson\"")
+	mergeCmd.Flags().StringVar(&mergeOutputFile, "output-file", "", "Path of the merged report, or \"-\" to write it to stdout (messages then go to stderr). Defaults to a timestamped file")
+	rootCmd.AddCommand(mergeCmd)
+}
+
+// runMerge merges the reports given as arguments
+func runMerge(cmd *cobra.Command, args []string) error {
+	mergeOutput = strings.ToLower(mergeOutput)
+	stream, err := ui.NewStreamFormatter(mergeOutput)
+	if err != nil {
+		return err
+	}
+	if mergeOutputFile == "" {
+		mergeOutputFile = fmt.Sprintf("gitlab-stats-merged-%s.%s", time.Now().Format("2006-01-02-15-04-05"), mergeOutput)
+	}
+	if mergeOutputFile == "-" {
+		ui.Console = os.Stderr
+	}
+
+	// Read every report before the output is opened, which may overwrite one of them
+	merged, err := services.MergeReports(args)
+	if err != nil {
+		return err
+	}
+
+	if setter, ok := stream.(ui.MetadataSetter); ok {
+		setter.SetMetadata(&models.ReportMetadata{
+			GitLabURL: merged.GitLabURL,
+			// An incremental scan based on the merged report must not miss activity
+			// since the oldest of the merged scans
+			StartedAt:         merged.OldestScan.UTC(),
+			CompletedAt:       time.Now().UTC(),
+			TotalProjects:     len(merged.Stats),
+			ProcessedProjects: len(merged.Stats),
+			Partial:           merged.Partial,
+			MergedFrom:        args,
+		})
+	}
+	if err := stream.Open(mergeOutputFile); err != nil {
+		return fmt.Errorf("failed to open output: %w", err)
+	}
+	for _, stat := range merged.Stats {
+		if err := stream.WriteRow(stat); err != nil {
+			stream.Close()
+			return err
+		}
+	}
+	if err := stream.Close(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	fmt.Fprintf(ui.Console, "Merged %d reports: %d projects (%d duplicate rows dropped)\n", len(args), len(merged.Stats), merged.Duplicates)
+	fmt.Fprintf(ui.Console, "Output written to: %s\n", describeOutput(mergeOutputFile))
+	return nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/services"
+	"github.com/mona-actions/gh-gitlab-stats/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	planCostModel  string
+	planGroupBy    string
+	planMaxHours   float64
+	planOutputFile string
+)
+
+// planCmd estimates migration times and plans migration waves from a report
+var planCmd = &cobra.Command{
+	Use:   "plan <report>",
+	Short: "Estimate migration times and plan migration waves",
+	Long: `Estimate the migration time of every project of a report written by an earlier
+scan (CSV, JSON or NDJSON) with a configurable cost model, and pack the projects
+into migration waves of at most --max-hours-per-wave estimated hours (a single
+project larger than that gets an over-budget wave of its own).
+
+Projects of the same namespace (or top-level group with --group-by group) are kept
+in the same wave when they fit. Projects with a "blocker" migration complexity are
+listed separately. The plan is written to a CSV file with one row per project.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPlan,
+}
+
+func init() {
+	planCmd.Flags().StringVar(&planCostModel, "cost-model", "", "Path to a JSON file overriding the migration cost model")
+	planCmd.Flags().StringVar(&planGroupBy, "group-by", services.PlanByNamespace, "Projects kept in the same wave: \"namespace\" or \"group\" (top-level group)")
+	planCmd.Flags().Float64Var(&planMaxHours, "max-hours-per-wave", 40, "Maximum estimated migration hours per wave")
+	planCmd.Flags().StringVar(&planOutputFile, "output-file", "", "Path of the wave plan CSV, or \"-\" to write it to stdout (the summary then goes to stderr). Defaults to a timestamped file")
+	rootCmd.AddCommand(planCmd)
+}
+
+// runPlan plans the migration of the report given as argument
+func runPlan(cmd *cobra.Command, args []string) error {
+	planGroupBy = strings.ToLower(planGroupBy)
+	if planGroupBy != services.PlanByNamespace && planGroupBy != services.PlanByGroup {
+		return fmt.Errorf("invalid group-by value: %s. Must be 'namespace' or 'group'", planGroupBy)
+	}
+	if planMaxHours <= 0 {
+		return fmt.Errorf("invalid max hours per wave: %v. Must be greater than 0", planMaxHours)
+	}
+	if planOutputFile == "" {
+		planOutputFile = fmt.Sprintf("gitlab-stats-plan-%s.csv", time.Now().Format("2006-01-02-15-04-05"))
+	}
+	if planOutputFile == "-" {
+		ui.Console = os.Stderr
+	}
+
+	model := services.DefaultCostModel()
+	if planCostModel != "" {
+		var err error
+		model, err = services.LoadCostModel(planCostModel)
+		if err != nil {
+			return err
+		}
+	}
+
+	report, err := ui.ReadReport(args[0])
+	if err != nil {
+		return err
+	}
+	if report.Metadata != nil && report.Metadata.Partial {
+		fmt.Fprintf(ui.Console, "Warning: %s is a partial report; projects its scan didn't reach are not planned\n", args[0])
+	}
+
+	plan := services.PlanWaves(report.Stats, model, planMaxHours, planGroupBy)
+	if err := ui.WritePlanCSV(plan, planOutputFile); err != nil {
+		return err
+	}
+
+	ui.PrintPlanSummary(ui.Console, plan, planMaxHours)
+	fmt.Fprintf(ui.Console, "Plan written to: %s\n", describeOutput(planOutputFile))
+	return nil
+}
@@ -4,7 +4,9 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -17,13 +19,40 @@ import (
 )
 
 var (
-	debug     bool
-	hostname  string
-	input     string
-	namespace string
-	output    string
-	repoList  string
-	token     string
+	activeSince        string
+	apiMode            string
+	archived           string
+	debug              bool
+	excludePatterns    []string
+	fullCounts         bool
+	hostname           string
+	includePatterns    []string
+	includePersonal    bool
+	inactiveFor        string
+	incremental        string
+	input              string
+	maxPages           int
+	maxRetries         int
+	membership         bool
+	mrConcurrency      int
+	namespace          string
+	output             string
+	outputFile         string
+	owned              bool
+	projectConcurrency int
+	repoList           string
+	requestsPerSecond  float64
+	resume             string
+	reviewDetails      bool
+	readinessConfig    string
+	search             string
+	skipEmpty          bool
+	skipForks          bool
+	starred            bool
+	summary            bool
+	token              string
+	visibility         []string
+	workers            int
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -55,13 +84,41 @@ func Execute() {
 
 func init() {
 	// Command flags matching the specification
+	rootCmd.Flags().StringVar(&activeSince, "active-since", "", "Only scan projects with activity on or after this date (YYYY-MM-DD)")
+	rootCmd.Flags().StringVar(&apiMode, "api", "rest", "GitLab API used for project statistics: \"rest\" or \"graphql\" (batches the project, MR count and release count requests and skips issue comments of projects without issues; every other metric still uses REST)")
+	rootCmd.Flags().StringVar(&archived, "archived", services.ArchivedInclude, "Archived projects: \"include\", \"only\" or \"exclude\"")
 	rootCmd.Flags().BoolVarP(&debug, "debug", "d", false, "Enable debug logging with detailed progress output")
+	rootCmd.Flags().StringArrayVar(&excludePatterns, "exclude", nil, "Skip projects whose full path matches this glob (e.g. \"**/sandbox-*\") or \"re:\" regular expression (repeatable)")
 	rootCmd.Flags().StringVarP(&hostname, "hostname", "H", "gitlab.com", "GitLab hostname (without https:// prefix)")
+	rootCmd.Flags().StringArrayVar(&includePatterns, "include", nil, "Only scan projects whose full path matches this glob (e.g. \"platform/**\") or \"re:\" regular expression (repeatable)")
+	rootCmd.Flags().BoolVar(&includePersonal, "include-personal-projects", false, "Also scan the personal projects of every user after the namespaces given by --namespace or --input (needs an administrator token to see private projects)")
+	rootCmd.Flags().StringVar(&inactiveFor, "inactive-for", "", "Only scan projects without activity for this long (e.g. 365d, 12w, 2y)")
+	rootCmd.Flags().StringVar(&incremental, "incremental", "", "Path to a previous CSV, JSON or NDJSON report; only projects with activity since that scan are rescanned and the others are copied from it")
 	rootCmd.Flags().StringVarP(&input, "input", "i", "", "Path to file with list of namespaces to scan (one per line)")
+	rootCmd.Flags().BoolVar(&fullCounts, "full-counts", false, "Walk every page when counting comments and reviews (same as --max-pages 0)")
+	rootCmd.Flags().IntVar(&maxPages, "max-pages", api.DefaultMaxPages, "Maximum pages of 100 MRs/issues walked for comment and review counts (0 = no limit); capped counts are listed in Truncated_Metrics")
+	rootCmd.Flags().IntVar(&maxRetries, "max-retries", api.DefaultMaxRetries, "Maximum number of retries for rate-limited (429) or failed (5xx) API requests")
+	rootCmd.Flags().BoolVar(&membership, "membership", false, "Only scan projects the token's user is a member of")
+	rootCmd.Flags().IntVar(&mrConcurrency, "mr-concurrency", api.DefaultMRConcurrency, "Number of merge requests of a project whose approvals and discussions are fetched in parallel with --review-details")
 	rootCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "GitLab namespace/group to analyze (e.g., \"mygroup/subgroup\")")
-	rootCmd.Flags().StringVarP(&output, "output", "O", "csv", "Output format: \"csv\" (timestamped file) or \"table\" (console)")
+	rootCmd.Flags().StringVarP(&output, "output", "O", "csv", "Output format: \"csv\", \"json\" or \"ndjson\" (timestamped file) or \"table\" (console)")
+	rootCmd.Flags().StringVar(&outputFile, "output-file", "", "Path of the report file, or \"-\" to write it to stdout (progress messages then go to stderr). Defaults to a timestamped file, or the console for table output")
+	rootCmd.Flags().BoolVar(&owned, "owned", false, "Only scan projects owned by the token's user")
+	rootCmd.Flags().IntVar(&projectConcurrency, "project-concurrency", api.DefaultProjectConcurrency, "Number of statistics requests made in parallel for each project")
 	rootCmd.Flags().StringVarP(&repoList, "repo-list", "r", "", "Path to file with list of repositories in \"namespace/project\" format (one per line)")
+	rootCmd.Flags().StringVar(&readinessConfig, "readiness-config", "", "Path to a JSON file overriding the migration readiness thresholds behind Migration_Complexity and Migration_Warnings")
+	rootCmd.Flags().BoolVar(&reviewDetails, "review-details", false, "Query the approvals and discussions of every merge request for real MR_Review_Count, MR_Discussion_Count and MR_Diff_Note_Count (two or more extra requests per MR)")
+	rootCmd.Flags().StringVar(&resume, "resume", "", "Path to a checkpoint file from an interrupted scan; already completed projects are not rescanned")
+	rootCmd.Flags().Float64Var(&requestsPerSecond, "requests-per-second", 0, "Maximum API requests per second shared by all workers (0 = no fixed limit; requests still slow down when GitLab reports a low rate limit budget)")
+	rootCmd.Flags().StringVar(&search, "search", "", "Only scan projects whose name or path contains this text")
+	rootCmd.Flags().BoolVar(&skipEmpty, "skip-empty", false, "Skip projects with an empty repository")
+	rootCmd.Flags().BoolVar(&skipForks, "skip-forks", false, "Skip projects forked from another project")
+	rootCmd.Flags().BoolVar(&starred, "starred", false, "Only scan projects starred by the token's user")
+	rootCmd.Flags().BoolVar(&summary, "summary", false, "Also write totals per top-level group and namespace, in the output format, to <report>-summary.<ext> (or after the table for table output)")
 	rootCmd.Flags().StringVarP(&token, "token", "t", "", "GitLab Personal Access Token (required, or set GITLAB_TOKEN env var)")
+	rootCmd.Flags().StringSliceVar(&visibility, "visibility", nil, "Only scan projects with these visibility levels: \"public\", \"internal\" and/or \"private\" (comma-separated)")
+	rootCmd.Flags().IntVarP(&workers, "workers", "w", services.DefaultWorkerCount, "Number of projects scanned in parallel")
+	rootCmd.MarkFlagsMutuallyExclusive("full-counts", "max-pages")
 }
 
 // runGLRepoStats is the main function that executes the GitLab repository statistics collection
@@ -73,9 +130,16 @@ func runGLRepoStats(cmd *cobra.Command, args []string) error {
 
 	// Normalize output format to lowercase for consistent internal use
 	output = strings.ToLower(output)
+	apiMode = strings.ToLower(apiMode)
+	archived = strings.ToLower(archived)
+
+	// Keep stdout clean for the report when it is written there
+	if outputFile == "-" {
+		ui.Console = os.Stderr
+	}
 
 	if debug {
-		fmt.Println("Debug mode enabled")
+		fmt.Fprintln(ui.Console, "Debug mode enabled")
 	}
 
 	// Validate inputs
@@ -83,24 +147,213 @@ func runGLRepoStats(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if fullCounts {
+		maxPages = 0
+	}
+
+	filter, err := services.NewProjectFilter(includePatterns, excludePatterns)
+	if err != nil {
+		return err
+	}
+	attributes, err := buildAttributeFilter()
+	if err != nil {
+		return err
+	}
+
+	readiness, err := newReadinessAnalyzer()
+	if err != nil {
+		return err
+	}
+
+	// Read the baseline before the output is opened, which may overwrite it
+	baseline, err := loadBaseline()
+	if err != nil {
+		return err
+	}
+
 	// Setup client and scanner
 	gitlabURL := buildGitLabURL()
-	client, err := api.NewRestClient(gitlabURL, token)
+	client, err := newClient(gitlabURL, &api.ClientOptions{
+		MaxRetries:         maxRetries,
+		RequestsPerSecond:  requestsPerSecond,
+		ProjectConcurrency: projectConcurrency,
+		MaxPages:           maxPages,
+		ReviewDetails:      reviewDetails,
+		MRConcurrency:      mrConcurrency,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to create GitLab client: %w", err)
 	}
 
+	// Timestamp shared by the output and checkpoint file names
+	startedAt := time.Now()
+	timestamp := startedAt.Format("2006-01-02-15-04-05")
+
+	checkpoint, err := openCheckpoint(timestamp)
+	if err != nil {
+		return err
+	}
+
+	// File output is streamed: each project is written as soon as it completes
+	outputFile = resolveOutputFile(timestamp)
+	stream, err := openStream(outputFile)
+	if err != nil {
+		checkpoint.Close()
+		return err
+	}
+
 	scanner := services.NewScanner(client)
+	scanner.SetCheckpoint(checkpoint)
+	scanner.SetFilter(filter)
+	scanner.SetAttributeFilter(attributes)
+	scanner.SetBaseline(baseline)
+	scanner.SetReadiness(readiness)
+	if stream != nil {
+		scanner.SetStreamFormatter(stream)
+	}
+
+	ctx, stopInterruptHandler := handleInterrupts(cmd.Context(), scanner, checkpoint)
+	defer stopInterruptHandler()
 
 	// Run scan
-	fmt.Printf("Starting GitLab repository statistics collection...\n")
-	allStats, err := executeScan(cmd.Context(), client, scanner, gitlabURL, output, debug)
+	fmt.Fprintf(ui.Console, "Starting GitLab repository statistics collection...\n")
+	result, err := executeScan(ctx, client, scanner, checkpoint, filter, attributes, baseline, readiness, stream, gitlabURL, output, debug)
 	if err != nil {
+		if stream != nil {
+			stream.Close()
+		}
+		keepCheckpoint(checkpoint)
 		return err
 	}
 
 	// Write output
-	return writeOutput(allStats)
+	metadata := buildMetadata(result, gitlabURL, startedAt)
+	if baseline != nil {
+		metadata.IncrementalSince = &baseline.Since
+	}
+	if err := writeOutput(result, stream, timestamp, metadata); err != nil {
+		keepCheckpoint(checkpoint)
+		return err
+	}
+	if summary {
+		if err := writeScanSummary(result, timestamp); err != nil {
+			keepCheckpoint(checkpoint)
+			return err
+		}
+	}
+
+	if result.Interrupted {
+		keepCheckpoint(checkpoint)
+		cmd.SilenceUsage = true
+		return fmt.Errorf("scan did not complete: %d discovered projects were not scanned", result.UnscannedCount())
+	}
+
+	// The report is complete, so the checkpoint is no longer needed
+	if err := checkpoint.Remove(); err != nil {
+		fmt.Fprintf(ui.Console, "Warning: Failed to remove checkpoint file %s: %v\n", checkpoint.Path(), err)
+	}
+	return nil
+}
+
+// newReadinessAnalyzer creates the migration readiness analyzer, with the thresholds
+// of --readiness-config if given
+func newReadinessAnalyzer() (*services.ReadinessAnalyzer, error) {
+	thresholds := services.DefaultReadinessThresholds()
+	if readinessConfig != "" {
+		var err error
+		thresholds, err = services.LoadReadinessThresholds(readinessConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return services.NewReadinessAnalyzer(thresholds), nil
+}
+
+// loadBaseline reads the report given by --incremental, if any
+func loadBaseline() (*services.Baseline, error) {
+	if incremental == "" {
+		return nil, nil
+	}
+
+	baseline, err := services.LoadBaseline(incremental, maxPages)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(ui.Console, "Incremental scan: %d projects in %s; rescanning projects with activity since %s\n",
+		baseline.Len(), incremental, baseline.Since.Format(time.RFC3339))
+	return baseline, nil
+}
+
+// resolveOutputFile returns where the report is written: the --output-file value,
+// the console for table output, or a timestamped file in the current directory
+func resolveOutputFile(timestamp string) string {
+	if outputFile != "" {
+		return outputFile
+	}
+	if output == "table" {
+		return "-"
+	}
+	return fmt.Sprintf("gitlab-stats-%s.%s", timestamp, output)
+}
+
+// openStream opens a streaming formatter for file based output formats.
+// It returns nil for console output, which is rendered once the scan completes.
+func openStream(filename string) (ui.StreamFormatter, error) {
+	if output == "table" {
+		return nil, nil
+	}
+
+	stream, err := ui.NewStreamFormatter(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create formatter: %w", err)
+	}
+	if err := stream.Open(filename); err != nil {
+		return nil, fmt.Errorf("failed to open output: %w", err)
+	}
+	if debug {
+		fmt.Fprintf(ui.Console, "Streaming results to: %s\n", filename)
+	}
+	return stream, nil
+}
+
+// openCheckpoint opens the checkpoint given by --resume, or creates a new one for this run
+func openCheckpoint(timestamp string) (*services.Checkpoint, error) {
+	path := fmt.Sprintf("gitlab-stats-%s.checkpoint", timestamp)
+	if resume != "" {
+		if _, err := os.Stat(resume); err != nil {
+			return nil, fmt.Errorf("failed to open checkpoint to resume: %w", err)
+		}
+		path = resume
+	}
+
+	checkpoint, err := services.OpenCheckpoint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if resume != "" {
+		fmt.Fprintf(ui.Console, "Resuming from checkpoint: %s (%d projects already completed)\n", path, checkpoint.Len())
+	} else if debug {
+		fmt.Fprintf(ui.Console, "Writing checkpoint to: %s\n", path)
+	}
+	return checkpoint, nil
+}
+
+// keepCheckpoint closes the checkpoint after a failed run and tells the user how to resume
+func keepCheckpoint(checkpoint *services.Checkpoint) {
+	if err := checkpoint.Close(); err != nil {
+		fmt.Fprintf(ui.Console, "Warning: Failed to close checkpoint file %s: %v\n", checkpoint.Path(), err)
+	}
+	fmt.Fprintf(ui.Console, "\nProgress saved to checkpoint: %s\n", checkpoint.Path())
+	fmt.Fprintf(ui.Console, "Resume the scan with: --resume %s\n", checkpoint.Path())
+}
+
+// newClient creates the GitLab client selected by --api
+func newClient(gitlabURL string, options *api.ClientOptions) (api.GitLabClient, error) {
+	if apiMode == "graphql" {
+		return api.NewGraphQLClient(gitlabURL, token, options)
+	}
+	return api.NewRestClient(gitlabURL, token, options)
 }
 
 // validateInputs validates command-line flags
@@ -108,12 +361,87 @@ func validateInputs() error {
 	if token == "" {
 		return fmt.Errorf("GitLab token is required. Use --token flag or set GITLAB_TOKEN environment variable")
 	}
-	if output != "csv" && output != "table" {
-		return fmt.Errorf("invalid output format: %s. Must be 'csv' or 'table'", output)
+	switch apiMode {
+	case "rest", "graphql":
+	default:
+		return fmt.Errorf("invalid API: %s. Must be 'rest' or 'graphql'", apiMode)
+	}
+	switch output {
+	case "csv", "json", "ndjson", "table":
+	default:
+		return fmt.Errorf("invalid output format: %s. Must be 'csv', 'json', 'ndjson' or 'table'", output)
+	}
+	if maxRetries < 0 {
+		return fmt.Errorf("invalid max retries: %d. Must be 0 or greater", maxRetries)
+	}
+	if requestsPerSecond < 0 {
+		return fmt.Errorf("invalid requests per second: %v. Must be 0 or greater", requestsPerSecond)
+	}
+	if workers < 1 {
+		return fmt.Errorf("invalid workers: %d. Must be 1 or greater", workers)
+	}
+	if projectConcurrency < 1 {
+		return fmt.Errorf("invalid project concurrency: %d. Must be 1 or greater", projectConcurrency)
+	}
+	if mrConcurrency < 1 {
+		return fmt.Errorf("invalid MR concurrency: %d. Must be 1 or greater", mrConcurrency)
+	}
+	if maxPages < 0 {
+		return fmt.Errorf("invalid max pages: %d. Must be 0 or greater", maxPages)
+	}
+	switch archived {
+	case services.ArchivedInclude, services.ArchivedOnly, services.ArchivedExclude:
+	default:
+		return fmt.Errorf("invalid archived mode: %s. Must be 'include', 'only' or 'exclude'", archived)
+	}
+	for i, level := range visibility {
+		visibility[i] = strings.ToLower(strings.TrimSpace(level))
+		switch visibility[i] {
+		case "public", "internal", "private":
+		default:
+			return fmt.Errorf("invalid visibility: %s. Must be 'public', 'internal' or 'private'", level)
+		}
 	}
 	return nil
 }
 
+// buildAttributeFilter builds the project attribute filter from the filter flags
+func buildAttributeFilter() (*services.AttributeFilter, error) {
+	attributes := &services.AttributeFilter{
+		Archived:   archived,
+		Visibility: visibility,
+		SkipForks:  skipForks,
+		SkipEmpty:  skipEmpty,
+		Owned:      owned,
+		Membership: membership,
+		Starred:    starred,
+		Search:     search,
+	}
+
+	if activeSince != "" {
+		since, err := utils.ParseDate(activeSince)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --active-since: %w", err)
+		}
+		attributes.ActiveSince = since
+	}
+	if inactiveFor != "" {
+		age, err := utils.ParseAge(inactiveFor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --inactive-for: %w", err)
+		}
+		attributes.ActiveBefore = time.Now().Add(-age)
+	}
+	if !attributes.ActiveSince.IsZero() && !attributes.ActiveBefore.IsZero() && !attributes.ActiveSince.Before(attributes.ActiveBefore) {
+		return nil, fmt.Errorf("--active-since %s and --inactive-for %s select no projects", activeSince, inactiveFor)
+	}
+
+	if repoList != "" && (owned || membership || starred || search != "") {
+		fmt.Fprintln(ui.Console, "Note: --owned, --membership, --starred and --search are evaluated by GitLab while listing projects and don't apply to --repo-list")
+	}
+	return attributes, nil
+}
+
 // buildGitLabURL constructs the GitLab URL from hostname
 func buildGitLabURL() string {
 	if strings.HasPrefix(hostname, "http://") || strings.HasPrefix(hostname, "https://") {
@@ -142,12 +470,12 @@ func readLinesFromFile(filename string) ([]string, error) {
 }
 
 // executeScan performs the repository scan based on input parameters
-func executeScan(ctx context.Context, client *api.RestClient, scanner *services.Scanner, gitlabURL, outputFormat string, verbose bool) ([]*models.RepositoryStats, error) {
+func executeScan(ctx context.Context, client api.GitLabClient, scanner *services.Scanner, checkpoint *services.Checkpoint, filter *services.ProjectFilter, attributes *services.AttributeFilter, baseline *services.Baseline, readiness *services.ReadinessAnalyzer, stream ui.StreamFormatter, gitlabURL, outputFormat string, verbose bool) (*models.ScanResult, error) {
 	progressReporter := createProgressReporter()
 
 	// Handle specific repository list
 	if repoList != "" {
-		return scanSpecificRepositories(ctx, client)
+		return scanSpecificRepositories(ctx, client, scanner, checkpoint, filter, attributes, baseline, readiness, stream)
 	}
 
 	// Handle namespaces
@@ -158,19 +486,25 @@ func executeScan(ctx context.Context, client *api.RestClient, scanner *services.
 
 	// Scan with server-side filtering for namespaces
 	if len(namespaces) == 0 {
+		if includePersonal {
+			fmt.Fprintln(ui.Console, "Note: --include-personal-projects only applies with --namespace or --input; a full scan already includes every personal project visible to the token")
+		}
+
 		// No namespace filter - scan all accessible projects
 		scanOptions := &models.ScanOptions{
 			GitLabURL:    gitlabURL,
 			Token:        token,
 			OutputFormat: outputFormat,
+			OutputFile:   outputFile,
 			Verbose:      verbose,
 			MaxProjects:  0,
+			Workers:      workers,
 		}
 		result, err := scanner.ScanRepositories(ctx, scanOptions, progressReporter)
 		if err != nil {
 			return nil, fmt.Errorf("scan failed: %w", err)
 		}
-		return result.RepositoryStats, nil
+		return result, nil
 	}
 
 	// Scan specific namespaces with server-side filtering
@@ -191,14 +525,14 @@ func getNamespacesToScan() ([]string, error) {
 			return nil, fmt.Errorf("failed to read namespaces from file %s: %w", input, err)
 		}
 		if debug {
-			fmt.Printf("Read %d namespaces from file: %s\n", len(namespaces), input)
+			fmt.Fprintf(ui.Console, "Read %d namespaces from file: %s\n", len(namespaces), input)
 		}
-		return namespaces, nil
+		return dedupNamespaces(namespaces), nil
 	}
 
 	if namespace != "" {
 		if debug {
-			fmt.Printf("Scanning namespace: %s\n", namespace)
+			fmt.Fprintf(ui.Console, "Scanning namespace: %s\n", namespace)
 		}
 		return []string{namespace}, nil
 	}
@@ -206,55 +540,189 @@ func getNamespacesToScan() ([]string, error) {
 	return nil, nil
 }
 
-// scanSpecificRepositories scans a list of specific repositories
-func scanSpecificRepositories(ctx context.Context, client *api.RestClient) ([]*models.RepositoryStats, error) {
+// dedupNamespaces drops namespaces listed more than once or nested in another listed
+// namespace. Group scans include subgroups, so scanning both "group" and
+// "group/sub" would list the projects of the subgroup twice.
+func dedupNamespaces(namespaces []string) []string {
+	normalized := make([]string, len(namespaces))
+	for i, ns := range namespaces {
+		// GitLab paths are case-insensitive
+		normalized[i] = strings.ToLower(strings.Trim(ns, "/"))
+	}
+
+	kept := make([]string, 0, len(namespaces))
+	for i, ns := range namespaces {
+		covered := ""
+		for j, other := range normalized {
+			if j == i {
+				continue
+			}
+			// Of two identical entries, the first one is kept
+			if (other == normalized[i] && j < i) || strings.HasPrefix(normalized[i], other+"/") {
+				covered = namespaces[j]
+				break
+			}
+		}
+		if covered != "" {
+			fmt.Fprintf(ui.Console, "Note: Skipping namespace %s, already included in %s\n", ns, covered)
+			continue
+		}
+		kept = append(kept, ns)
+	}
+	return kept
+}
+
+// scanSpecificRepositories scans a list of specific repositories, skipping those
+// rejected by the include/exclude or attribute filters
+func scanSpecificRepositories(ctx context.Context, client api.GitLabClient, scanner *services.Scanner, checkpoint *services.Checkpoint, filter *services.ProjectFilter, attributes *services.AttributeFilter, baseline *services.Baseline, readiness *services.ReadinessAnalyzer, stream ui.StreamFormatter) (*models.ScanResult, error) {
 	repositories, err := readLinesFromFile(repoList)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read repositories from file %s: %w", repoList, err)
 	}
 
 	if debug {
-		fmt.Printf("Read %d repositories from file: %s\n", len(repositories), repoList)
+		fmt.Fprintf(ui.Console, "Read %d repositories from file: %s\n", len(repositories), repoList)
 	}
 
-	var allStats []*models.RepositoryStats
+	selected := make([]string, 0, len(repositories))
 	for _, repoPath := range repositories {
+		if filter.Match(repoPath) {
+			selected = append(selected, repoPath)
+		} else if debug {
+			fmt.Fprintf(ui.Console, "Excluded by filters: %s\n", repoPath)
+		}
+	}
+
+	result := &models.ScanResult{
+		TotalProjects:    len(selected),
+		ExcludedProjects: len(repositories) - len(selected),
+	}
+	repositories = selected
+	// Projects listed more than once, possibly under an old path, are scanned once
+	scanned := make(map[int]bool, len(repositories))
+	stopped := func() bool { return scanner.Interrupted() || ctx.Err() != nil }
+	// stopAt ends the scan at the i-th repository, leaving it and the rest unscanned
+	stopAt := func(i int) {
+		result.Interrupted = true
+		result.Unscanned = append(result.Unscanned, repositories[i:]...)
+	}
+	for i, repoPath := range repositories {
+		if stopped() {
+			stopAt(i)
+			break
+		}
+
 		if debug {
-			fmt.Printf("Scanning repository: %s\n", repoPath)
+			fmt.Fprintf(ui.Console, "Scanning repository: %s\n", repoPath)
 		}
 
 		if strings.Count(repoPath, "/") < 1 {
-			fmt.Printf("Warning: Invalid repository path format: %s (expected namespace/project)\n", repoPath)
+			fmt.Fprintf(ui.Console, "Warning: Invalid repository path format: %s (expected namespace/project)\n", repoPath)
 			continue
 		}
 
 		project, err := client.GetProject(ctx, repoPath)
+		if err != nil && stopped() {
+			// The request was abandoned because of the interrupt
+			stopAt(i)
+			break
+		}
 		if err != nil {
-			fmt.Printf("Warning: Failed to get project %s: %v\n", repoPath, err)
+			fmt.Fprintf(ui.Console, "Warning: Failed to get project %s: %v\n", repoPath, err)
+			continue
+		}
+
+		if scanned[project.ID] {
+			if debug {
+				fmt.Fprintf(ui.Console, "Already scanned: %s\n", repoPath)
+			}
+			result.TotalProjects--
+			continue
+		}
+		scanned[project.ID] = true
+
+		if !attributes.Match(project) {
+			if debug {
+				fmt.Fprintf(ui.Console, "Excluded by filters: %s\n", repoPath)
+			}
+			result.TotalProjects--
+			result.ExcludedProjects++
+			continue
+		}
+
+		if stat, ok := checkpoint.Get(project.ID); ok {
+			if debug {
+				fmt.Fprintf(ui.Console, "Restored from checkpoint: %s\n", repoPath)
+			}
+			readiness.Assess(stat)
+			result.RepositoryStats = append(result.RepositoryStats, stat)
+			result.ResumedProjects++
+			writeRow(stream, stat)
+			continue
+		}
+
+		if stat, ok := baseline.Unchanged(project); ok {
+			if debug {
+				fmt.Fprintf(ui.Console, "Unchanged since baseline: %s\n", repoPath)
+			}
+			readiness.Assess(stat)
+			result.RepositoryStats = append(result.RepositoryStats, stat)
+			result.UnchangedProjects++
+			writeRow(stream, stat)
 			continue
 		}
 
 		stats, err := client.GetProjectStatistics(ctx, project.ID)
+		if err != nil && stopped() {
+			// The request was abandoned because of the interrupt
+			stopAt(i)
+			break
+		}
 		if err != nil {
-			fmt.Printf("Warning: Failed to get statistics for %s: %v\n", repoPath, err)
+			fmt.Fprintf(ui.Console, "Warning: Failed to get statistics for %s: %v\n", repoPath, err)
 			continue
 		}
 
 		repoStats := services.ConvertToRepoStats(project, stats)
-		allStats = append(allStats, repoStats)
+		readiness.Assess(repoStats)
+		result.RepositoryStats = append(result.RepositoryStats, repoStats)
+		if err := checkpoint.Record(repoStats); err != nil {
+			fmt.Fprintf(ui.Console, "Warning: %v\n", err)
+		}
+		writeRow(stream, repoStats)
 	}
 
-	return allStats, nil
+	result.ProcessedProjects = len(result.RepositoryStats)
+	return result, nil
+}
+
+// writeRow streams a completed project to the output file, if output is streamed
+func writeRow(stream ui.StreamFormatter, stat *models.RepositoryStats) {
+	if stream == nil {
+		return
+	}
+	if err := stream.WriteRow(stat); err != nil {
+		fmt.Fprintf(ui.Console, "Warning: %v\n", err)
+	}
 }
 
 // scanNamespaces scans specific namespaces and returns results
 // Uses server-side filtering for efficiency - no client-side filtering needed
-func scanNamespaces(ctx context.Context, scanner *services.Scanner, gitlabURL, outputFormat string, verbose bool, progressReporter ui.ProgressReporter, namespaces []string) ([]*models.RepositoryStats, error) {
-	var allStats []*models.RepositoryStats
+func scanNamespaces(ctx context.Context, scanner *services.Scanner, gitlabURL, outputFormat string, verbose bool, progressReporter ui.ProgressReporter, namespaces []string) (*models.ScanResult, error) {
+	combined := &models.ScanResult{}
+
+	for i, ns := range namespaces {
+		if scanner.Interrupted() || ctx.Err() != nil {
+			// Projects in namespaces that were never discovered can't be listed individually
+			combined.Interrupted = true
+			for _, skipped := range namespaces[i:] {
+				combined.Unscanned = append(combined.Unscanned, "# namespace not scanned: "+skipped)
+			}
+			break
+		}
 
-	for _, ns := range namespaces {
 		if verbose {
-			fmt.Printf("Processing namespace: %s (using server-side filtering)\n", ns)
+			fmt.Fprintf(ui.Console, "Processing namespace: %s (using server-side filtering)\n", ns)
 		}
 
 		// Create scan options with namespace for server-side filtering
@@ -263,8 +731,10 @@ func scanNamespaces(ctx context.Context, scanner *services.Scanner, gitlabURL, o
 			Token:        token,
 			Namespace:    ns, // Server-side filtering by namespace
 			OutputFormat: outputFormat,
+			OutputFile:   outputFile,
 			Verbose:      verbose,
 			MaxProjects:  0,
+			Workers:      workers,
 		}
 
 		result, err := scanner.ScanRepositories(ctx, scanOptions, progressReporter)
@@ -273,50 +743,203 @@ func scanNamespaces(ctx context.Context, scanner *services.Scanner, gitlabURL, o
 		}
 
 		// No client-side filtering needed - server already filtered by namespace
-		allStats = append(allStats, result.RepositoryStats...)
+		combineResults(combined, result)
+	}
+
+	if includePersonal {
+		if combined.Interrupted || scanner.Interrupted() || ctx.Err() != nil {
+			combined.Interrupted = true
+			combined.Unscanned = append(combined.Unscanned, "# personal projects not scanned")
+			return combined, nil
+		}
+
+		if verbose {
+			fmt.Fprintln(ui.Console, "Processing the personal projects of every user")
+		}
+
+		scanOptions := &models.ScanOptions{
+			GitLabURL:        gitlabURL,
+			Token:            token,
+			PersonalProjects: true,
+			OutputFormat:     outputFormat,
+			OutputFile:       outputFile,
+			Verbose:          verbose,
+			MaxProjects:      0,
+			Workers:          workers,
+		}
+
+		result, err := scanner.ScanRepositories(ctx, scanOptions, progressReporter)
+		if err != nil {
+			return nil, fmt.Errorf("scan failed for personal projects: %w", err)
+		}
+		combineResults(combined, result)
 	}
 
-	return allStats, nil
+	return combined, nil
+}
+
+// combineResults adds the results of one scan to those of the whole run
+func combineResults(combined, result *models.ScanResult) {
+	combined.RepositoryStats = append(combined.RepositoryStats, result.RepositoryStats...)
+	combined.TotalProjects += result.TotalProjects
+	combined.ProcessedProjects += result.ProcessedProjects
+	combined.ResumedProjects += result.ResumedProjects
+	combined.ExcludedProjects += result.ExcludedProjects
+	combined.UnchangedProjects += result.UnchangedProjects
+	combined.Errors = append(combined.Errors, result.Errors...)
+	combined.Duration += result.Duration
+	combined.Interrupted = combined.Interrupted || result.Interrupted
+	combined.Unscanned = append(combined.Unscanned, result.Unscanned...)
 }
 
-// writeOutput writes the scan results to the appropriate output format
-func writeOutput(allStats []*models.RepositoryStats) error {
+// writeOutput finalizes the scan results in the appropriate output format.
+// File output has already been streamed row by row; results of an interrupted
+// scan are renamed to a clearly marked partial file and accompanied by the list
+// of projects that were not scanned.
+func writeOutput(result *models.ScanResult, stream ui.StreamFormatter, timestamp string, metadata *models.ReportMetadata) error {
+	allStats := result.RepositoryStats
+	ext := filepath.Ext(outputFile)
+	baseName := strings.TrimSuffix(outputFile, ext)
+	if outputFile == "-" {
+		baseName = fmt.Sprintf("gitlab-stats-%s", timestamp)
+	}
+	if result.Interrupted {
+		baseName += "-partial"
+		if err := writeUnscanned(result, baseName+"-unscanned.txt"); err != nil {
+			return err
+		}
+	}
+
 	if output == "table" {
-		return outputTable(allStats)
+		if result.Interrupted {
+			fmt.Fprintf(ui.Console, "\n⚠ PARTIAL RESULTS: the scan was interrupted before all projects were processed\n\n")
+		}
+		return writeTable(allStats)
 	}
 
-	// CSV output
-	outputFile := fmt.Sprintf("gitlab-stats-%s.csv", time.Now().Format("2006-01-02-15-04-05"))
-	formatter, err := ui.NewFormatter("csv")
-	if err != nil {
-		return fmt.Errorf("failed to create formatter: %w", err)
+	if setter, ok := stream.(ui.MetadataSetter); ok {
+		setter.SetMetadata(metadata)
+	}
+	if err := stream.Close(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
 	}
+	if result.Interrupted && outputFile != "-" {
+		partialFile := baseName + ext
+		if err := os.Rename(outputFile, partialFile); err != nil {
+			return fmt.Errorf("failed to mark output as partial: %w", err)
+		}
+		outputFile = partialFile
+	}
+
+	if result.Interrupted {
+		fmt.Fprintf(ui.Console, "\n⚠ Scan interrupted - partial results written.\n")
+	} else {
+		fmt.Fprintf(ui.Console, "\nScan completed successfully!\n")
+	}
+	fmt.Fprintf(ui.Console, "Total repositories processed: %d\n", len(allStats))
+	fmt.Fprintf(ui.Console, "Output written to: %s\n", describeOutput(outputFile))
+	return nil
+}
 
-	if err := formatter.WriteToFile(allStats, outputFile); err != nil {
+// writeTable renders the table report to the output file or console
+func writeTable(stats []*models.RepositoryStats) error {
+	out, err := ui.CreateOutput(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to open output: %w", err)
+	}
+	if err := outputTable(out, stats); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
 		return fmt.Errorf("failed to write output: %w", err)
 	}
+	if outputFile != "-" {
+		fmt.Fprintf(ui.Console, "Output written to: %s\n", outputFile)
+	}
+	return nil
+}
+
+// writeScanSummary writes the per-namespace summary of a scan next to the report
+func writeScanSummary(result *models.ScanResult, timestamp string) error {
+	filename := "-"
+	if outputFile != "-" {
+		ext := filepath.Ext(outputFile)
+		filename = strings.TrimSuffix(outputFile, ext) + "-summary" + ext
+	} else if output != "table" {
+		filename = fmt.Sprintf("gitlab-stats-%s-summary.%s", timestamp, output)
+		if result.Interrupted {
+			filename = fmt.Sprintf("gitlab-stats-%s-partial-summary.%s", timestamp, output)
+		}
+	}
+
+	if filename == "-" {
+		fmt.Println()
+	}
+	if err := ui.WriteSummary(services.SummarizeStats(result.RepositoryStats), output, filename); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	if filename != "-" {
+		fmt.Fprintf(ui.Console, "Summary written to: %s\n", filename)
+	}
+	return nil
+}
+
+// describeOutput returns a human readable name for an output destination
+func describeOutput(filename string) string {
+	if filename == "-" {
+		return "stdout"
+	}
+	return filename
+}
+
+// buildMetadata describes the completed scan for formats that include metadata
+func buildMetadata(result *models.ScanResult, gitlabURL string, startedAt time.Time) *models.ReportMetadata {
+	completedAt := time.Now()
+	return &models.ReportMetadata{
+		GitLabURL:         gitlabURL,
+		StartedAt:         startedAt.UTC(),
+		CompletedAt:       completedAt.UTC(),
+		DurationSeconds:   completedAt.Sub(startedAt).Seconds(),
+		TotalProjects:     result.TotalProjects,
+		ProcessedProjects: len(result.RepositoryStats),
+		ExcludedProjects:  result.ExcludedProjects,
+		UnchangedProjects: result.UnchangedProjects,
+		ErrorCount:        len(result.Errors),
+		Partial:           result.Interrupted,
+		MaxPages:          &maxPages,
+	}
+}
 
-	fmt.Printf("\nScan completed successfully!\n")
-	fmt.Printf("Total repositories processed: %d\n", len(allStats))
-	fmt.Printf("Output written to: %s\n", outputFile)
+// writeUnscanned writes the projects that were not scanned in --repo-list format,
+// so they can be scanned separately
+func writeUnscanned(result *models.ScanResult, filename string) error {
+	content := "# Projects not scanned because the scan was interrupted\n"
+	if len(result.Unscanned) > 0 {
+		content += strings.Join(result.Unscanned, "\n") + "\n"
+	}
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write unscanned projects list: %w", err)
+	}
+	fmt.Fprintf(ui.Console, "Unscanned projects (%d) written to: %s\n", result.UnscannedCount(), filename)
 	return nil
 }
 
 // outputTable outputs the results in table format
-func outputTable(stats []*models.RepositoryStats) error {
+func outputTable(w io.Writer, stats []*models.RepositoryStats) error {
 	if len(stats) == 0 {
-		fmt.Println("No repositories found.")
+		fmt.Fprintln(w, "No repositories found.")
 		return nil
 	}
 
 	// Table header
-	fmt.Printf("%-30s %-30s %-10s %-15s %-15s %-10s %-10s %-15s %-10s %-10s\n",
-		"Namespace", "Repository", "Empty", "Size(MB)", "LFS(MB)", "Commits", "Issues", "MRs", "Branches", "Tags")
-	fmt.Println(strings.Repeat("-", 155))
+	fmt.Fprintf(w, "%-30s %-30s %-10s %-15s %-15s %-10s %-10s %-15s %-10s %-10s %s\n",
+		"Namespace", "Repository", "Empty", "Size(MB)", "LFS(MB)", "Commits", "Issues", "MRs", "Branches", "Tags", "Complexity")
+	fmt.Fprintln(w, strings.Repeat("-", 166))
 
 	// Table rows
 	for _, stat := range stats {
-		fmt.Printf("%-30s %-30s %-10v %-15.2f %-15.2f %-10d %-10d %-15d %-10d %-10d\n",
+		fmt.Fprintf(w, "%-30s %-30s %-10v %-15.2f %-15.2f %-10d %-10d %-15d %-10d %-10d %s\n",
 			utils.Truncate(stat.Namespace, 30),
 			utils.Truncate(stat.RepoName, 30),
 			stat.IsEmpty,
@@ -326,9 +949,10 @@ func outputTable(stats []*models.RepositoryStats) error {
 			stat.IssueCount,
 			stat.MRCount,
 			stat.BranchCount,
-			stat.TagCount)
+			stat.TagCount,
+			stat.MigrationComplexity)
 	}
 
-	fmt.Printf("\nTotal repositories: %d\n", len(stats))
+	fmt.Fprintf(w, "\nTotal repositories: %d\n", len(stats))
 	return nil
 }
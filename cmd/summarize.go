@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/services"
+	"github.com/mona-actions/gh-gitlab-stats/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	summarizeOutput     string
+	summarizeOutputFile string
+)
+
+// summarizeCmd rolls an existing report up per group and namespace
+var summarizeCmd = &cobra.Command{
+	Use:   "summarize <report>",
+	Short: "Summarize a scan report per group and namespace",
+	Long: `Roll the projects of a report written by an earlier scan (CSV, JSON or NDJSON)
+up per top-level group and per namespace: project counts, total, average and
+largest repository and LFS sizes, archived, empty and fork counts, total issues,
+merge requests and commits, and the most recently active project.
+
+This is the summary a scan writes with --summary.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSummarize,
+}
+
+func init() {
+	summarizeCmd.Flags().StringVarP(&summarizeOutput, "output", "O", "table", "Output format: \"table\" (console), \"csv\", \"json\" or \"ndjson\"")
+	summarizeCmd.Flags().StringVar(&summarizeOutputFile, "output-file", "", "Path of the summary, or \"-\" to write it to stdout. Defaults to a timestamped file, or the console for table output")
+	rootCmd.AddCommand(summarizeCmd)
+}
+
+// runSummarize summarizes the report given as argument
+func runSummarize(cmd *cobra.Command, args []string) error {
+	summarizeOutput = strings.ToLower(summarizeOutput)
+	switch summarizeOutput {
+	case "table", "csv", "json", "ndjson":
+	default:
+		return fmt.Errorf("invalid output format: %s. Must be 'table', 'csv', 'json' or 'ndjson'", summarizeOutput)
+	}
+	if summarizeOutputFile == "" {
+		summarizeOutputFile = "-"
+		if summarizeOutput != "table" {
+			summarizeOutputFile = fmt.Sprintf("gitlab-stats-summary-%s.%s", time.Now().Format("2006-01-02-15-04-05"), summarizeOutput)
+		}
+	}
+	if summarizeOutputFile == "-" {
+		ui.Console = os.Stderr
+	}
+
+	report, err := ui.ReadReport(args[0])
+	if err != nil {
+		return err
+	}
+	if report.Metadata != nil && report.Metadata.Partial {
+		fmt.Fprintf(ui.Console, "Warning: %s is a partial report; the summary only covers the projects it contains\n", args[0])
+	}
+
+	if err := ui.WriteSummary(services.SummarizeStats(report.Stats), summarizeOutput, summarizeOutputFile); err != nil {
+		return err
+	}
+	if summarizeOutputFile != "-" {
+		fmt.Fprintf(ui.Console, "Summary written to: %s\n", summarizeOutputFile)
+	}
+	return nil
+}
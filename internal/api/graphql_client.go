@@ -0,0 +1,297 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// GraphQLBatchSize is the number of projects whose statistics are requested in a
+	// single GraphQL query, kept well below GitLab's query complexity limit
+	GraphQLBatchSize = 25
+)
+
+// graphQLMetrics are the count metrics answered by the GraphQL query, along with the
+// project's statistics that REST would fetch from /projects/:id; every other metric
+// is collected through the REST API
+var graphQLMetrics = map[string]bool{
+	"Issue_Count":   true,
+	"MR_Count":      true,
+	"Release_Count": true,
+}
+
+// projectStatisticsQuery fetches the statistics available through GraphQL for a batch of projects
+const projectStatisticsQuery = `query($ids: [ID!], $first: Int) {
+  projects(ids: $ids, first: $first) {
+    nodes {
+      id
+      wikiEnabled
+      statistics {
+        commitCount
+        repositorySize
+        lfsObjectsSize
+        wikiSize
+        storageSize
+        buildArtifactsSize
+      }
+      openIssues: issues(state: opened) { count }
+      issues { count }
+      mergeRequests { count }
+      releases { count }
+    }
+  }
+}`
+
+// StatisticsPrefetcher is implemented by clients that can fetch the statistics of
+// many projects at once. Prefetch announces the projects that are about to be
+// scanned so that later GetProjectStatistics calls can be answered in batches;
+// projects that are never requested stay in memory, so only announce projects
+// that are being handed to workers.
+type StatisticsPrefetcher interface {
+	Prefetch(projectIDs []int)
+}
+
+// GraphQLClient implements GitLabClient using GitLab's GraphQL API for the project
+// statistics it exposes, falling back to the REST API for everything else
+type GraphQLClient struct {
+	*RestClient
+
+	mu      sync.Mutex
+	batches map[int]*graphQLBatch // Pending batches by project ID
+}
+
+// graphQLBatch is a group of projects whose statistics are fetched by one query.
+// The query isn't tied to the context of any of the callers waiting for it; done
+// is closed once it finished.
+type graphQLBatch struct {
+	ids     []int
+	once    sync.Once
+	done    chan struct{}
+	results map[int]*graphQLProject
+	err     error
+}
+
+func newGraphQLBatch(ids []int) *graphQLBatch {
+	return &graphQLBatch{ids: ids, done: make(chan struct{})}
+}
+
+// graphQLProject is a project node of the statistics query
+type graphQLProject struct {
+	ID          string `json:"id"`
+	WikiEnabled bool   `json:"wikiEnabled"`
+	Statistics  *struct {
+		CommitCount        float64 `json:"commitCount"`
+		RepositorySize     float64 `json:"repositorySize"`
+		LFSObjectsSize     float64 `json:"lfsObjectsSize"`
+		WikiSize           float64 `json:"wikiSize"`
+		StorageSize        float64 `json:"storageSize"`
+		BuildArtifactsSize float64 `json:"buildArtifactsSize"`
+	} `json:"statistics"`
+	OpenIssues *struct {
+		Count int `json:"count"`
+	} `json:"openIssues"`
+	Issues *struct {
+		Count int `json:"count"`
+	} `json:"issues"`
+	MergeRequests *struct {
+		Count int `json:"count"`
+	} `json:"mergeRequests"`
+	Releases *struct {
+		Count int `json:"count"`
+	} `json:"releases"`
+}
+
+// graphQLResponse is the envelope of a GraphQL response
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// NewGraphQLClient creates a new GraphQL API based GitLab client.
+// If options is nil, default settings are used.
+func NewGraphQLClient(baseURL, token string, options *ClientOptions) (*GraphQLClient, error) {
+	rest, err := NewRestClient(baseURL, token, options)
+	if err != nil {
+		return nil, err
+	}
+	return &GraphQLClient{
+		RestClient: rest,
+		batches:    make(map[int]*graphQLBatch),
+	}, nil
+}
+
+// Prefetch groups the given projects into batches of GraphQLBatchSize. Each batch
+// is queried when the first of its projects is requested, and a project's entry is
+// removed when it is requested.
+func (c *GraphQLClient) Prefetch(projectIDs []int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for start := 0; start < len(projectIDs); start += GraphQLBatchSize {
+		end := start + GraphQLBatchSize
+		if end > len(projectIDs) {
+			end = len(projectIDs)
+		}
+		batch := newGraphQLBatch(projectIDs[start:end])
+		for _, id := range batch.ids {
+			c.batches[id] = batch
+		}
+	}
+}
+
+// GetProjectStatistics fetches the commit count, storage sizes and the open issue,
+// MR and release counts through GraphQL and collects the remaining metrics through
+// REST; the issue comments are only walked when the project has issues. If GraphQL
+// cannot answer for a project, REST is used for all of it.
+func (c *GraphQLClient) GetProjectStatistics(ctx context.Context, projectID interface{}) (*ProjectStatistics, error) {
+	id, ok := projectID.(int)
+	if !ok {
+		return c.RestClient.GetProjectStatistics(ctx, projectID)
+	}
+
+	project, err := c.graphQLProject(ctx, id)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Printf("Warning: GraphQL statistics unavailable for project %d, falling back to REST: %v", id, err)
+		return c.RestClient.GetProjectStatistics(ctx, projectID)
+	}
+
+	skip := graphQLMetrics
+	if project.Issues.Count == 0 {
+		skip = maps.Clone(graphQLMetrics)
+		skip["Issue_Comment_Count"] = true
+	}
+
+	stats := project.toStatistics()
+	c.collectStatistics(ctx, projectID, project.WikiEnabled, stats, skip)
+	return stats, nil
+}
+
+// graphQLProject returns the GraphQL statistics of a project, querying its batch
+// (or the project alone if it was not prefetched) on first use. The query runs
+// without the caller's cancellation, so a cancelled caller doesn't fail the other
+// projects of its batch; the caller itself stops waiting when ctx is done.
+func (c *GraphQLClient) graphQLProject(ctx context.Context, id int) (*graphQLProject, error) {
+	c.mu.Lock()
+	batch, ok := c.batches[id]
+	if ok {
+		delete(c.batches, id)
+	} else {
+		batch = newGraphQLBatch([]int{id})
+	}
+	c.mu.Unlock()
+
+	batch.once.Do(func() {
+		go func() {
+			defer close(batch.done)
+			batch.results, batch.err = c.queryProjects(context.WithoutCancel(ctx), batch.ids)
+		}()
+	})
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if batch.err != nil {
+		return nil, batch.err
+	}
+
+	project, ok := batch.results[id]
+	if !ok || project.Statistics == nil || project.OpenIssues == nil || project.Issues == nil ||
+		project.MergeRequests == nil || project.Releases == nil {
+		return nil, fmt.Errorf("project %d missing from GraphQL response", id)
+	}
+	return project, nil
+}
+
+// queryProjects runs the statistics query for the given projects
+func (c *GraphQLClient) queryProjects(ctx context.Context, ids []int) (map[int]*graphQLProject, error) {
+	globalIDs := make([]string, len(ids))
+	for i, id := range ids {
+		globalIDs[i] = fmt.Sprintf("gid://gitlab/Project/%d", id)
+	}
+
+	var data struct {
+		Projects struct {
+			Nodes []*graphQLProject `json:"nodes"`
+		} `json:"projects"`
+	}
+	variables := map[string]interface{}{"ids": globalIDs, "first": len(ids)}
+	if err := c.doGraphQL(ctx, projectStatisticsQuery, variables, &data); err != nil {
+		return nil, err
+	}
+
+	results := make(map[int]*graphQLProject, len(data.Projects.Nodes))
+	for _, project := range data.Projects.Nodes {
+		if project == nil {
+			continue
+		}
+		id, err := strconv.Atoi(project.ID[strings.LastIndex(project.ID, "/")+1:])
+		if err != nil {
+			continue
+		}
+		results[id] = project
+	}
+	return results, nil
+}
+
+// doGraphQL posts a query to the GraphQL endpoint and decodes its data into out.
+// Errors reported alongside partial data are logged; the data is still used.
+func (c *GraphQLClient) doGraphQL(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	payload, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return fmt.Errorf("failed to encode GraphQL query: %w", err)
+	}
+
+	apiURL := fmt.Sprintf("%s/api/graphql", c.baseURL)
+	body, _, err := c.doWithRetry(ctx, "POST", apiURL, "/graphql", payload)
+	if err != nil {
+		return err
+	}
+
+	var response graphQLResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("failed to parse GraphQL response: %w", err)
+	}
+
+	messages := make([]string, len(response.Errors))
+	for i, e := range response.Errors {
+		messages[i] = e.Message
+	}
+	if len(response.Data) == 0 || string(response.Data) == "null" {
+		return fmt.Errorf("GraphQL query failed: %s", strings.Join(messages, "; "))
+	}
+	if len(messages) > 0 {
+		log.Printf("Warning: GraphQL query returned errors: %s", strings.Join(messages, "; "))
+	}
+
+	if err := json.Unmarshal(response.Data, out); err != nil {
+		return fmt.Errorf("failed to parse GraphQL data: %w", err)
+	}
+	return nil
+}
+
+// toStatistics converts the GraphQL fields to the statistics model
+func (p *graphQLProject) toStatistics() *ProjectStatistics {
+	return &ProjectStatistics{
+		CommitCount:       int(p.Statistics.CommitCount),
+		StorageSize:       int64(p.Statistics.StorageSize),
+		RepositorySize:    int64(p.Statistics.RepositorySize),
+		WikiSize:          int64(p.Statistics.WikiSize),
+		LFSObjectsSize:    int64(p.Statistics.LFSObjectsSize),
+		JobArtifactsSize:  int64(p.Statistics.BuildArtifactsSize),
+		IssueCount:        p.OpenIssues.Count,
+		MergeRequestCount: p.MergeRequests.Count,
+		ReleaseCount:      p.Releases.Count,
+	}
+}
@@ -0,0 +1,248 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeGraphQLServer is a fake GitLab answering the statistics query and the REST
+// endpoints. Project N has N commits through GraphQL and 100+N through REST, and
+// only odd projects have issues.
+type fakeGraphQLServer struct {
+	*httptest.Server
+
+	missing map[int]bool  // Projects left out of GraphQL responses
+	failing bool          // GraphQL queries fail entirely
+	release chan struct{} // If set, GraphQL queries wait until it is closed
+
+	mu            sync.Mutex
+	batches       []int        // Number of projects of every GraphQL query
+	restProjects  []int        // Projects fetched from /projects/:id
+	issueRequests map[int]bool // Projects whose issues were walked
+}
+
+func newFakeGraphQLServer(t *testing.T) *fakeGraphQLServer {
+	t.Helper()
+	f := &fakeGraphQLServer{missing: make(map[int]bool), issueRequests: make(map[int]bool)}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeGraphQLServer) serve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Path == "/api/graphql" {
+		f.serveGraphQL(w, r)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v4/projects/"), "/")
+	id, _ := strconv.Atoi(parts[0])
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case len(parts) == 1:
+		f.restProjects = append(f.restProjects, id)
+		fmt.Fprintf(w, `{"id":%d,"open_issues_count":%d,"statistics":{"commit_count":%d}}`, id, id, 100+id)
+	case parts[1] == "issues":
+		f.issueRequests[id] = true
+		fmt.Fprint(w, "[]")
+	default:
+		fmt.Fprint(w, "[]")
+	}
+}
+
+func (f *fakeGraphQLServer) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Variables struct {
+			IDs []string `json:"ids"`
+		} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if f.release != nil {
+		<-f.release
+	}
+
+	f.mu.Lock()
+	f.batches = append(f.batches, len(request.Variables.IDs))
+	f.mu.Unlock()
+	if f.failing {
+		fmt.Fprint(w, `{"data":null,"errors":[{"message":"query too complex"}]}`)
+		return
+	}
+
+	nodes := []string{}
+	for _, globalID := range request.Variables.IDs {
+		id, _ := strconv.Atoi(strings.TrimPrefix(globalID, "gid://gitlab/Project/"))
+		if f.missing[id] {
+			continue
+		}
+		nodes = append(nodes, fmt.Sprintf(`{"id":%q,"wikiEnabled":false,"statistics":{"commitCount":%d},`+
+			`"openIssues":{"count":%d},"issues":{"count":%d},"mergeRequests":{"count":3},"releases":{"count":1}}`,
+			globalID, id, id%2, 2*(id%2)))
+	}
+	fmt.Fprintf(w, `{"data":{"projects":{"nodes":[%s]}}}`, strings.Join(nodes, ","))
+}
+
+func newTestGraphQLClient(t *testing.T, baseURL string) *GraphQLClient {
+	t.Helper()
+	client, err := NewGraphQLClient(baseURL, "token", nil)
+	if err != nil {
+		t.Fatalf("NewGraphQLClient: %v", err)
+	}
+	return client
+}
+
+// scanProjects fetches the statistics of the given projects concurrently, like the scanner's workers
+func scanProjects(t *testing.T, client *GraphQLClient, ids []int) map[int]*ProjectStatistics {
+	t.Helper()
+	stats := make([]*ProjectStatistics, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if stats[i], err = client.GetProjectStatistics(context.Background(), id); err != nil {
+				t.Errorf("GetProjectStatistics(%d): %v", id, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	byID := make(map[int]*ProjectStatistics, len(ids))
+	for i, id := range ids {
+		byID[id] = stats[i]
+	}
+	return byID
+}
+
+func TestGraphQLClientBatching(t *testing.T) {
+	server := newFakeGraphQLServer(t)
+	client := newTestGraphQLClient(t, server.URL)
+
+	ids := make([]int, 30)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	client.Prefetch(ids)
+	stats := scanProjects(t, client, ids)
+
+	slices.Sort(server.batches)
+	if !slices.Equal(server.batches, []int{5, GraphQLBatchSize}) {
+		t.Errorf("got GraphQL queries for %v projects, want [5 %d]", server.batches, GraphQLBatchSize)
+	}
+	if len(server.restProjects) > 0 {
+		t.Errorf("projects %v were fetched through REST", server.restProjects)
+	}
+	for _, id := range ids {
+		if stats[id] == nil {
+			continue
+		}
+		if stats[id].CommitCount != id || stats[id].MergeRequestCount != 3 || stats[id].ReleaseCount != 1 {
+			t.Errorf("project %d: got %d commits, %d MRs, %d releases", id, stats[id].CommitCount, stats[id].MergeRequestCount, stats[id].ReleaseCount)
+		}
+		hasIssues := id%2 == 1
+		if wantIssues := id % 2; stats[id].IssueCount != wantIssues {
+			t.Errorf("project %d: IssueCount = %d, want %d", id, stats[id].IssueCount, wantIssues)
+		}
+		if server.issueRequests[id] != hasIssues {
+			t.Errorf("project %d: issues walked = %v, want %v", id, server.issueRequests[id], hasIssues)
+		}
+	}
+	if len(client.batches) != 0 {
+		t.Errorf("%d projects are still pending", len(client.batches))
+	}
+}
+
+func TestGraphQLClientFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		missing  []int
+		failing  bool
+		wantREST []int
+	}{
+		{"missing node", []int{2}, false, []int{2}},
+		{"failed query", nil, true, []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeGraphQLServer(t)
+			for _, id := range tt.missing {
+				server.missing[id] = true
+			}
+			server.failing = tt.failing
+			client := newTestGraphQLClient(t, server.URL)
+
+			ids := []int{1, 2, 3}
+			client.Prefetch(ids)
+			stats := scanProjects(t, client, ids)
+
+			slices.Sort(server.restProjects)
+			if !slices.Equal(server.restProjects, tt.wantREST) {
+				t.Errorf("projects %v were fetched through REST, want %v", server.restProjects, tt.wantREST)
+			}
+			for _, id := range ids {
+				want := id
+				if slices.Contains(tt.wantREST, id) {
+					want = 100 + id
+				}
+				if stats[id] != nil && stats[id].CommitCount != want {
+					t.Errorf("project %d: CommitCount = %d, want %d", id, stats[id].CommitCount, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGraphQLClientCancelledCaller(t *testing.T) {
+	server := newFakeGraphQLServer(t)
+	server.release = make(chan struct{})
+	t.Cleanup(func() {
+		select {
+		case <-server.release:
+		default:
+			close(server.release)
+		}
+	})
+	client := newTestGraphQLClient(t, server.URL)
+	client.Prefetch([]int{1, 2})
+
+	// The first caller starts the batch's query and gives up while it runs
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() {
+		_, err := client.graphQLProject(ctx, 1)
+		errs <- err
+	}()
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled caller got %v, want %v", err, context.Canceled)
+	}
+
+	// The query still completes for the other project of the batch
+	close(server.release)
+	project, err := client.graphQLProject(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("graphQLProject(2): %v", err)
+	}
+	if project.Statistics.CommitCount != 2 {
+		t.Errorf("CommitCount = %v, want 2", project.Statistics.CommitCount)
+	}
+	if len(server.batches) != 1 {
+		t.Errorf("got %d GraphQL queries, want 1", len(server.batches))
+	}
+}
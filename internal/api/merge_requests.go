@@ -0,0 +1,243 @@
+package api
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// mergeRequestActivity accumulates the metrics derived from a single traversal
+// of a project's merge requests
+type mergeRequestActivity struct {
+	approvals  int
+	userNotes  int
+	open       int
+	merged     int
+	closed     int
+	drafts     int
+	mergeHours []float64 // Time from creation to merge for every merged MR
+
+	// Only collected when review details are enabled
+	discussions       int
+	diffNotes         int
+	truncated         bool  // A discussion list stopped at the page limit
+	approvalsFailed   int   // MRs whose approvals couldn't be fetched
+	discussionsFailed int   // MRs whose discussions couldn't be fetched
+	reviewErr         error // First error fetching review details
+}
+
+// mergeRequestReviews holds the review activity of a single merge request,
+// collected from its approvals and discussions endpoints
+type mergeRequestReviews struct {
+	approvals      int
+	discussions    int
+	diffNotes      int
+	truncated      bool
+	approvalsErr   error // Why the approvals couldn't be fetched, if they couldn't
+	discussionsErr error // Why the discussions couldn't be fetched, if they couldn't
+}
+
+// add records a single merge request
+func (a *mergeRequestActivity) add(mr *MergeRequest) {
+	a.approvals += len(mr.ApprovedBy)
+	a.userNotes += mr.UserNotesCount
+
+	switch mr.State {
+	case "opened", "locked":
+		a.open++
+	case "merged":
+		a.merged++
+		if mr.CreatedAt != nil && mr.MergedAt != nil {
+			a.mergeHours = append(a.mergeHours, mr.MergedAt.Sub(*mr.CreatedAt).Hours())
+		}
+	case "closed":
+		a.closed++
+	}
+
+	// work_in_progress is the pre-14.0 name of the draft flag
+	if mr.Draft || mr.WorkInProgress {
+		a.drafts++
+	}
+}
+
+// apply copies the accumulated metrics into stats
+func (a *mergeRequestActivity) apply(stats *ProjectStatistics) {
+	stats.MergeRequestReviewCount = a.approvals
+	stats.MergeRequestCommentCount = a.userNotes
+	stats.MergeRequestOpenCount = a.open
+	stats.MergeRequestMergedCount = a.merged
+	stats.MergeRequestClosedCount = a.closed
+	stats.MergeRequestDraftCount = a.drafts
+	stats.MergeRequestAvgMergeHours, stats.MergeRequestMedianMergeHours = meanAndMedian(a.mergeHours)
+	stats.MergeRequestDiscussionCount = a.discussions
+	stats.MergeRequestDiffNoteCount = a.diffNotes
+}
+
+// addReviews replaces the approvals reported in the MR list with those from the
+// approvals endpoint and records discussion activity. A failed endpoint only
+// affects its own metrics: the MR keeps the approvals of the list, or adds no
+// discussions.
+func (a *mergeRequestActivity) addReviews(mr *MergeRequest, reviews mergeRequestReviews) {
+	if reviews.approvalsErr != nil {
+		a.approvalsFailed++
+		a.reviewErr = cmp.Or(a.reviewErr, reviews.approvalsErr)
+	} else {
+		a.approvals += reviews.approvals - len(mr.ApprovedBy)
+	}
+
+	if reviews.discussionsErr != nil {
+		a.discussionsFailed++
+		a.reviewErr = cmp.Or(a.reviewErr, reviews.discussionsErr)
+		return
+	}
+	a.discussions += reviews.discussions
+	a.diffNotes += reviews.diffNotes
+	a.truncated = a.truncated || reviews.truncated
+}
+
+// failedMetrics returns the metrics left incomplete by merge requests whose review
+// details couldn't be fetched
+func (a *mergeRequestActivity) failedMetrics() []string {
+	var metrics []string
+	if a.approvalsFailed > 0 {
+		metrics = append(metrics, "MR_Review_Count")
+	}
+	if a.discussionsFailed > 0 {
+		metrics = append(metrics, "MR_Discussion_Count", "MR_Diff_Note_Count")
+	}
+	return metrics
+}
+
+// getMergeRequestActivity walks a project's merge requests once, storing approval,
+// comment, state, draft and time-to-merge metrics in stats. The returned count is
+// the number of MR comments; the other metrics share its truncation. With review
+// details enabled, the approvals and discussions of every MR are fetched as well,
+// up to mrConcurrency MRs at a time; metrics an MR's review details couldn't be
+// fetched for are added to the failed metrics of stats.
+func (c *RestClient) getMergeRequestActivity(ctx context.Context, projectID interface{}, stats *ProjectStatistics) (countResult, error) {
+	params := url.Values{}
+	params.Set("scope", "all")
+
+	activity := &mergeRequestActivity{}
+	path := fmt.Sprintf("/projects/%s/merge_requests", c.encodeProjectID(projectID))
+	truncated, err := c.walkPages(ctx, path, params, func(body []byte) (int, error) {
+		var mrs []*MergeRequest
+		if err := json.Unmarshal(body, &mrs); err != nil {
+			return 0, fmt.Errorf("failed to parse merge requests: %w", err)
+		}
+		for _, mr := range mrs {
+			activity.add(mr)
+		}
+		if !c.reviewDetails {
+			return len(mrs), nil
+		}
+
+		reviews := make([]mergeRequestReviews, len(mrs))
+		c.forEachConcurrently(len(mrs), c.mrConcurrency, func(i int) {
+			reviews[i] = c.getMergeRequestReviews(ctx, path, mrs[i].IID)
+		})
+		for i, mr := range mrs {
+			activity.addReviews(mr, reviews[i])
+		}
+		return len(mrs), ctx.Err()
+	})
+	if err != nil {
+		return countResult{}, err
+	}
+
+	if failed := activity.failedMetrics(); len(failed) > 0 {
+		log.Printf("Warning: Failed to get the review details of %d merge requests of project %v: %v",
+			max(activity.approvalsFailed, activity.discussionsFailed), projectID, activity.reviewErr)
+		stats.FailedMetrics = append(stats.FailedMetrics, failed...)
+	}
+
+	activity.apply(stats)
+	return countResult{Value: activity.userNotes, Truncated: truncated || activity.truncated}, nil
+}
+
+// getMergeRequestReviews collects the approvals and discussions of a single merge
+// request. mrsPath is the project's merge requests endpoint. Endpoints that fail
+// are reported in approvalsErr and discussionsErr. A merge request deleted since
+// it was listed answers both endpoints with 404 Not Found and counts nothing; a
+// 404 from only one of them (e.g. approvals unavailable on the instance) is a
+// failure of that endpoint.
+func (c *RestClient) getMergeRequestReviews(ctx context.Context, mrsPath string, iid int) mergeRequestReviews {
+	reviews := mergeRequestReviews{}
+
+	body, _, approvalsErr := c.doRequest(ctx, "GET", fmt.Sprintf("%s/%d/approvals", mrsPath, iid), nil)
+	if approvalsErr == nil {
+		var approvals MergeRequestApprovals
+		if err := json.Unmarshal(body, &approvals); err != nil {
+			reviews.approvalsErr = fmt.Errorf("failed to parse approvals of merge request !%d: %w", iid, err)
+		}
+		reviews.approvals = len(approvals.ApprovedBy)
+	}
+
+	var discussionsErr error
+	reviews.truncated, discussionsErr = c.walkPages(ctx, fmt.Sprintf("%s/%d/discussions", mrsPath, iid), nil, func(body []byte) (int, error) {
+		var discussions []*Discussion
+		if err := json.Unmarshal(body, &discussions); err != nil {
+			return 0, fmt.Errorf("failed to parse discussions: %w", err)
+		}
+		for _, discussion := range discussions {
+			countDiscussion(discussion, &reviews)
+		}
+		return len(discussions), nil
+	})
+
+	if isStatus(approvalsErr, http.StatusNotFound) && isStatus(discussionsErr, http.StatusNotFound) {
+		return mergeRequestReviews{}
+	}
+	if approvalsErr != nil {
+		reviews.approvalsErr = fmt.Errorf("failed to get approvals of merge request !%d: %w", iid, approvalsErr)
+	}
+	if discussionsErr != nil {
+		reviews.discussionsErr = fmt.Errorf("failed to get discussions of merge request !%d: %w", iid, discussionsErr)
+	}
+	return reviews
+}
+
+// countDiscussion adds a discussion to the review counts. System notes (e.g. "added
+// 1 commit") are ignored, and standalone comments are not counted as threads.
+func countDiscussion(discussion *Discussion, reviews *mergeRequestReviews) {
+	userNotes := 0
+	for _, note := range discussion.Notes {
+		if note.System {
+			continue
+		}
+		userNotes++
+		if note.Type == "DiffNote" {
+			reviews.diffNotes++
+		}
+	}
+	if userNotes > 0 && !discussion.IndividualNote {
+		reviews.discussions++
+	}
+}
+
+// meanAndMedian returns the mean and median of values, or zeros when there are none
+func meanAndMedian(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+
+	mid := len(sorted) / 2
+	median := sorted[mid]
+	if len(sorted)%2 == 0 {
+		median = (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sum / float64(len(sorted)), median
+}
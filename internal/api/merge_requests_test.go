@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestGetMergeRequestActivityReviews(t *testing.T) {
+	tests := []struct {
+		name            string
+		approvals       int // Status of MR !2's approvals endpoint
+		discussions     int // Status of MR !2's discussions endpoint
+		wantReviews     int
+		wantDiscussions int
+		wantFailed      []string
+	}{
+		{"available", http.StatusOK, http.StatusOK, 2, 2, nil},
+		{"deleted", http.StatusNotFound, http.StatusNotFound, 1, 1, nil},
+		{"approvals not found", http.StatusNotFound, http.StatusOK, 1, 2, []string{"MR_Review_Count"}},
+		{"approvals forbidden", http.StatusForbidden, http.StatusOK, 1, 2, []string{"MR_Review_Count"}},
+		{"discussions not found", http.StatusOK, http.StatusNotFound, 2, 1, []string{"MR_Discussion_Count", "MR_Diff_Note_Count"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Two merge requests with one approval and one discussion thread each
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v4/projects/1/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[{"iid":1,"state":"opened"},{"iid":2,"state":"merged"}]`)
+			})
+			for iid, statuses := range map[int][2]int{1: {http.StatusOK, http.StatusOK}, 2: {tt.approvals, tt.discussions}} {
+				mux.HandleFunc(fmt.Sprintf("/api/v4/projects/1/merge_requests/%d/approvals", iid), func(w http.ResponseWriter, r *http.Request) {
+					if statuses[0] != http.StatusOK {
+						http.Error(w, `{"message":"error"}`, statuses[0])
+						return
+					}
+					fmt.Fprint(w, `{"approved_by":[{"user":{"id":1}}]}`)
+				})
+				mux.HandleFunc(fmt.Sprintf("/api/v4/projects/1/merge_requests/%d/discussions", iid), func(w http.ResponseWriter, r *http.Request) {
+					if statuses[1] != http.StatusOK {
+						http.Error(w, `{"message":"error"}`, statuses[1])
+						return
+					}
+					fmt.Fprint(w, `[{"individual_note":false,"notes":[{"type":"DiffNote"},{"type":"DiscussionNote"}]}]`)
+				})
+			}
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			client := newTestClient(t, server.URL)
+			client.reviewDetails = true
+			stats := &ProjectStatistics{}
+			if _, err := client.getMergeRequestActivity(context.Background(), 1, stats); err != nil {
+				t.Fatalf("getMergeRequestActivity: %v", err)
+			}
+
+			if stats.MergeRequestReviewCount != tt.wantReviews {
+				t.Errorf("MergeRequestReviewCount = %d, want %d", stats.MergeRequestReviewCount, tt.wantReviews)
+			}
+			if stats.MergeRequestDiscussionCount != tt.wantDiscussions {
+				t.Errorf("MergeRequestDiscussionCount = %d, want %d", stats.MergeRequestDiscussionCount, tt.wantDiscussions)
+			}
+			if !slices.Equal(stats.FailedMetrics, tt.wantFailed) {
+				t.Errorf("FailedMetrics = %v, want %v", stats.FailedMetrics, tt.wantFailed)
+			}
+		})
+	}
+}
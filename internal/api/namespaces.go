@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// GetNamespace resolves a namespace path (e.g., "mygroup/subgroup" or "jdoe") to a
+// group or user namespace. Tokens without access to the namespaces API entry of a
+// group (e.g., a public group the user isn't a member of) fall back to the groups API.
+func (c *RestClient) GetNamespace(ctx context.Context, namespacePath string) (*Namespace, error) {
+	path := fmt.Sprintf("/namespaces/%s", url.PathEscape(namespacePath))
+
+	body, _, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		if !isStatus(err, http.StatusNotFound, http.StatusForbidden) {
+			return nil, fmt.Errorf("failed to get namespace %s: %w", namespacePath, err)
+		}
+		group, groupErr := c.GetGroupByPath(ctx, namespacePath)
+		if groupErr != nil {
+			return nil, fmt.Errorf("failed to get namespace %s: %w", namespacePath, err)
+		}
+		return &Namespace{
+			ID:       group.ID,
+			Name:     group.Name,
+			Path:     group.Path,
+			Kind:     NamespaceKindGroup,
+			FullPath: group.FullPath,
+		}, nil
+	}
+
+	var namespace Namespace
+	if err := json.Unmarshal(body, &namespace); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace response: %w", err)
+	}
+
+	return &namespace, nil
+}
+
+// GetCurrentUser retrieves the user the token belongs to
+func (c *RestClient) GetCurrentUser(ctx context.Context) (*User, error) {
+	body, _, err := c.doRequest(ctx, "GET", "/user", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	var user User
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, fmt.Errorf("failed to parse user response: %w", err)
+	}
+
+	return &user, nil
+}
+
+// ListUsers retrieves a page of users, ordered by ID. Administrators see every
+// user, including blocked ones; other tokens only see active users.
+func (c *RestClient) ListUsers(ctx context.Context, page, perPage int) ([]*User, error) {
+	params := url.Values{}
+	params.Set("order_by", "id")
+	params.Set("sort", "asc")
+	params.Set("page", strconv.Itoa(page))
+	params.Set("per_page", strconv.Itoa(perPage))
+
+	body, _, err := c.doRequest(ctx, "GET", "/users", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	var users []*User
+	if err := json.Unmarshal(body, &users); err != nil {
+		return nil, fmt.Errorf("failed to parse users response: %w", err)
+	}
+
+	return users, nil
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// LowRateLimitRatio is the fraction of the GitLab rate limit budget below which
+	// requests are spread out evenly until the limit window resets
+	LowRateLimitRatio = 0.1
+)
+
+// RateLimiter is a token bucket shared by every request made through a client.
+// It enforces an optional fixed request rate and additionally slows all callers
+// down when GitLab's RateLimit-* headers report that the remaining budget is low.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	// Fixed rate token bucket (disabled when rate is 0)
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// Adaptive throttling driven by RateLimit-* response headers
+	throttleInterval time.Duration
+	throttleUntil    time.Time
+	nextSlot         time.Time
+}
+
+// NewRateLimiter creates a rate limiter allowing requestsPerSecond requests on average.
+// A rate of 0 disables the fixed limit; adaptive throttling is always active.
+func NewRateLimiter(requestsPerSecond float64) *RateLimiter {
+	burst := requestsPerSecond
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent or the context is cancelled
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve(time.Now())
+		if delay <= 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a request slot if one is available and returns 0, otherwise it
+// returns how long the caller should wait before trying again
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Adaptive throttling: space requests evenly over the rest of the limit window
+	throttled := l.throttleInterval > 0 && now.Before(l.throttleUntil)
+	if throttled && now.Before(l.nextSlot) {
+		return l.nextSlot.Sub(now)
+	}
+
+	if l.rate > 0 {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+
+		if l.tokens < 1 {
+			return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		}
+		l.tokens--
+	}
+
+	if throttled {
+		l.nextSlot = now.Add(l.throttleInterval)
+	}
+	return 0
+}
+
+// Observe updates the adaptive throttle from the RateLimit-* headers of a response
+func (l *RateLimiter) Observe(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	limit, err := strconv.Atoi(header.Get("RateLimit-Limit"))
+	if err != nil || limit <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if float64(remaining) > float64(limit)*LowRateLimitRatio {
+		l.throttleInterval = 0
+		return
+	}
+
+	resetUnix, err := strconv.ParseInt(header.Get("RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	reset := time.Unix(resetUnix, 0)
+	window := time.Until(reset)
+	if window <= 0 {
+		return
+	}
+
+	interval := window / time.Duration(remaining+1)
+	if l.throttleInterval == 0 {
+		log.Printf("Rate limit budget low (%d/%d remaining), slowing requests to one every %v until %s",
+			remaining, limit, interval.Round(time.Millisecond), reset.Format(time.TimeOnly))
+	}
+	l.throttleInterval = interval
+	l.throttleUntil = reset
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterTokenBucket(t *testing.T) {
+	tests := []struct {
+		name              string
+		requestsPerSecond float64
+		want              []time.Duration // Delay of consecutive reservations at the same instant
+	}{
+		{"unlimited", 0, []time.Duration{0, 0, 0, 0}},
+		{"burst", 2, []time.Duration{0, 0, 500 * time.Millisecond}},
+		{"below one per second", 0.5, []time.Duration{0, 2 * time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRateLimiter(tt.requestsPerSecond)
+			now := limiter.last
+			for i, want := range tt.want {
+				if got := limiter.reserve(now); got != want {
+					t.Errorf("reservation %d: delay %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterRefill(t *testing.T) {
+	limiter := NewRateLimiter(4)
+	now := limiter.last
+	for i := 0; i < 4; i++ {
+		limiter.reserve(now)
+	}
+
+	if got := limiter.reserve(now); got != 250*time.Millisecond {
+		t.Fatalf("empty bucket: delay %v, want 250ms", got)
+	}
+	// A token was added after the delay
+	if got := limiter.reserve(now.Add(250 * time.Millisecond)); got != 0 {
+		t.Errorf("after refill: delay %v, want 0", got)
+	}
+	// The bucket never holds more than the burst
+	later := now.Add(time.Hour)
+	for i := 0; i < 4; i++ {
+		if got := limiter.reserve(later); got != 0 {
+			t.Errorf("refilled reservation %d: delay %v, want 0", i, got)
+		}
+	}
+	if got := limiter.reserve(later); got == 0 {
+		t.Error("reservation beyond the burst was not delayed")
+	}
+}
+
+// rateLimitHeader returns the RateLimit-* headers of a response
+func rateLimitHeader(remaining, limit int, reset time.Time) http.Header {
+	header := http.Header{}
+	header.Set("RateLimit-Remaining", strconv.Itoa(remaining))
+	header.Set("RateLimit-Limit", strconv.Itoa(limit))
+	header.Set("RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	return header
+}
+
+func TestRateLimiterAdaptiveThrottle(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining int
+		throttled bool
+	}{
+		{"plenty remaining", 500, false},
+		{"just above threshold", 101, false},
+		{"at threshold", 100, true},
+		{"nearly exhausted", 9, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRateLimiter(0)
+			reset := time.Now().Add(11 * time.Second)
+			limiter.Observe(rateLimitHeader(tt.remaining, 1000, reset))
+
+			now := time.Now()
+			if got := limiter.reserve(now); got != 0 {
+				t.Fatalf("first reservation: delay %v, want 0", got)
+			}
+			delay := limiter.reserve(now)
+			if !tt.throttled {
+				if delay != 0 {
+					t.Errorf("second reservation: delay %v, want 0", delay)
+				}
+				return
+			}
+
+			// The remaining budget is spread evenly until the reset (whole seconds)
+			window := time.Until(time.Unix(reset.Unix(), 0))
+			want := window / time.Duration(tt.remaining+1)
+			if delay <= 0 || delay > want+want/10 || delay < want-want/10 {
+				t.Errorf("second reservation: delay %v, want about %v", delay, want)
+			}
+		})
+	}
+}
+
+func TestRateLimiterThrottleLifted(t *testing.T) {
+	limiter := NewRateLimiter(0)
+	limiter.Observe(rateLimitHeader(1, 1000, time.Now().Add(time.Minute)))
+	now := time.Now()
+	limiter.reserve(now)
+	if limiter.reserve(now) == 0 {
+		t.Fatal("low budget did not throttle")
+	}
+
+	// The limit window reset and the budget is back
+	limiter.Observe(rateLimitHeader(999, 1000, time.Now().Add(time.Minute)))
+	if got := limiter.reserve(now); got != 0 {
+		t.Errorf("after reset: delay %v, want 0", got)
+	}
+
+	// Throttling also ends at the reset time without a new response
+	limiter.Observe(rateLimitHeader(1, 1000, time.Now().Add(2*time.Second)))
+	limiter.reserve(now)
+	if got := limiter.reserve(now.Add(3 * time.Second)); got != 0 {
+		t.Errorf("after reset time: delay %v, want 0", got)
+	}
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	limiter := NewRateLimiter(0.001)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait = %v, want %v", err, context.Canceled)
+	}
+}
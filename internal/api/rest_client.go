@@ -1,65 +1,167 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 const (
 	// DefaultPageSize is the default number of items per page for API requests
 	DefaultPageSize = 100
-	// MaxPagesPerQuery is the maximum number of pages to fetch for expensive operations
+	// DefaultMaxPages is the default maximum number of pages to fetch for expensive operations
 	// to avoid excessive API calls (e.g., for MR comments, issue comments)
-	MaxPagesPerQuery = 10
+	DefaultMaxPages = 10
 	// DefaultHTTPTimeout is the default timeout for HTTP requests
 	DefaultHTTPTimeout = 120 * time.Second
+	// DefaultProjectConcurrency is the default number of concurrent API requests made per project
+	DefaultProjectConcurrency = 1
+	// DefaultMRConcurrency is the default number of merge requests whose review details are fetched in parallel
+	DefaultMRConcurrency = 4
+	// MaxCountPages is the highest page probed when counting a collection without an X-Total header
+	MaxCountPages = 1 << 16
 )
 
 // GitLabClient interface defines the contract for GitLab API interactions
 type GitLabClient interface {
-	ListProjects(ctx context.Context, options *ListProjectsOptions) ([]*Project, error)
+	ListProjects(ctx context.Context, options *ListProjectsOptions) (*ProjectPage, error)
 	GetProject(ctx context.Context, projectID interface{}) (*Project, error)
 	GetProjectStatistics(ctx context.Context, projectID interface{}) (*ProjectStatistics, error)
 	GetGroupByPath(ctx context.Context, groupPath string) (*Group, error)
+	GetNamespace(ctx context.Context, namespacePath string) (*Namespace, error)
+	GetCurrentUser(ctx context.Context) (*User, error)
+	ListUsers(ctx context.Context, page, perPage int) ([]*User, error)
 }
 
 // ListProjectsOptions contains options for listing projects
 type ListProjectsOptions struct {
-	GroupID           *int
-	Membership        *bool
-	Owned             *bool
-	Starred           *bool
-	Archived          *bool
-	Visibility        *string
-	OrderBy           *string
-	Sort              *string
-	Search            *string
-	Statistics        *bool
-	WithIssues        *bool
-	WithMergeRequests *bool
-	Page              int
-	PerPage           int
+	GroupID            *int
+	User               string // Lists the personal projects of a user, given by ID or username
+	Membership         *bool
+	Owned              *bool
+	Starred            *bool
+	Archived           *bool
+	Visibility         *string
+	OrderBy            *string
+	Sort               *string
+	Search             *string
+	LastActivityAfter  *time.Time
+	LastActivityBefore *time.Time
+	Statistics         *bool
+	WithIssues         *bool
+	WithMergeRequests  *bool
+	Page               int
+	PerPage            int
+	// Pagination set to "keyset" requests keyset pagination ordered by project ID.
+	// Page is then ignored and Cursor selects the page instead.
+	Pagination string
+	// Cursor is the NextCursor of the previous keyset page (empty for the first page)
+	Cursor string
+}
+
+// ProjectPage is a page of projects returned by ListProjects
+type ProjectPage struct {
+	Projects []*Project
+	// Keyset reports whether the page was served with keyset pagination. It is false
+	// when keyset pagination was requested but isn't supported by the server, in which
+	// case the caller should continue with offset pagination.
+	Keyset bool
+	// NextCursor selects the next keyset page; it is empty on the last page
+	NextCursor string
+}
+
+// ClientOptions contains optional settings for the REST client
+type ClientOptions struct {
+	// MaxRetries is the number of times a rate-limited or failed request is retried (0 disables retries)
+	MaxRetries int
+	// RequestsPerSecond limits the request rate across all callers (0 means no fixed limit)
+	RequestsPerSecond float64
+	// ProjectConcurrency is the number of statistics requests made in parallel for a single project
+	ProjectConcurrency int
+	// MaxPages limits the pages walked for comment and review counts (0 walks every page)
+	MaxPages int
+	// ReviewDetails fetches the approvals and discussions of every merge request
+	ReviewDetails bool
+	// MRConcurrency is the number of merge requests of a project whose review details are fetched in parallel
+	MRConcurrency int
+}
+
+// HTTPError is returned when the GitLab API responds with a non-2xx status
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// isStatus reports whether err is an HTTPError with one of the given status codes
+func isStatus(err error, statusCodes ...int) bool {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	for _, code := range statusCodes {
+		if httpErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
 }
 
 // RestClient implements GitLabClient using direct REST API calls
 type RestClient struct {
-	baseURL    string
-	token      string
-	httpClient *http.Client
+	baseURL            string
+	token              string
+	httpClient         *http.Client
+	maxRetries         int
+	limiter            *RateLimiter
+	projectConcurrency int
+	mrConcurrency      int
+	maxPages           int
+	reviewDetails      bool
 }
 
-// NewRestClient creates a new REST API based GitLab client
-func NewRestClient(baseURL, token string) (*RestClient, error) {
+// NewRestClient creates a new REST API based GitLab client.
+// If options is nil, default settings are used.
+func NewRestClient(baseURL, token string, options *ClientOptions) (*RestClient, error) {
 	if baseURL == "" {
 		baseURL = "https://gitlab.com"
 	}
+	if options == nil {
+		options = &ClientOptions{
+			MaxRetries:         DefaultMaxRetries,
+			ProjectConcurrency: DefaultProjectConcurrency,
+			MaxPages:           DefaultMaxPages,
+			MRConcurrency:      DefaultMRConcurrency,
+		}
+	}
+	if options.MaxRetries < 0 {
+		return nil, fmt.Errorf("max retries must not be negative: %d", options.MaxRetries)
+	}
+	if options.RequestsPerSecond < 0 {
+		return nil, fmt.Errorf("requests per second must not be negative: %v", options.RequestsPerSecond)
+	}
+	if options.ProjectConcurrency < 1 {
+		return nil, fmt.Errorf("project concurrency must be at least 1: %d", options.ProjectConcurrency)
+	}
+	if options.MRConcurrency < 1 {
+		return nil, fmt.Errorf("merge request concurrency must be at least 1: %d", options.MRConcurrency)
+	}
+	if options.MaxPages < 0 {
+		return nil, fmt.Errorf("max pages must not be negative: %d", options.MaxPages)
+	}
 
 	return &RestClient{
 		baseURL: baseURL,
@@ -67,6 +169,12 @@ func NewRestClient(baseURL, token string) (*RestClient, error) {
 		httpClient: &http.Client{
 			Timeout: DefaultHTTPTimeout,
 		},
+		maxRetries:         options.MaxRetries,
+		limiter:            NewRateLimiter(options.RequestsPerSecond),
+		maxPages:           options.MaxPages,
+		reviewDetails:      options.ReviewDetails,
+		projectConcurrency: options.ProjectConcurrency,
+		mrConcurrency:      options.MRConcurrency,
 	}, nil
 }
 
@@ -78,7 +186,8 @@ func (c *RestClient) encodeProjectID(projectID interface{}) string {
 	return fmt.Sprintf("%v", projectID)
 }
 
-// doRequest performs an HTTP request with authentication
+// doRequest performs an HTTP request with authentication, retrying transient
+// failures (429, 5xx and network errors) with exponential backoff
 func (c *RestClient) doRequest(ctx context.Context, method, path string, params url.Values) ([]byte, *http.Response, error) {
 	// Build full URL
 	apiURL := fmt.Sprintf("%s/api/v4%s", c.baseURL, path)
@@ -86,8 +195,46 @@ func (c *RestClient) doRequest(ctx context.Context, method, path string, params
 		apiURL = fmt.Sprintf("%s?%s", apiURL, params.Encode())
 	}
 
+	return c.doWithRetry(ctx, method, apiURL, path, nil)
+}
+
+// doWithRetry sends a request to apiURL, retrying transient failures. path is only
+// used for logging; payload, if not nil, is sent as a JSON request body.
+func (c *RestClient) doWithRetry(ctx context.Context, method, apiURL, path string, payload []byte) ([]byte, *http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		body, resp, err := c.executeRequest(ctx, method, apiURL, payload)
+		if err == nil {
+			return body, resp, nil
+		}
+
+		// Give up on cancellation, non-transient errors, or once retries are exhausted
+		if ctx.Err() != nil || attempt >= c.maxRetries {
+			return body, resp, err
+		}
+		if resp != nil && !isRetryableStatus(resp.StatusCode) {
+			return body, resp, err
+		}
+
+		delay := retryDelay(resp, attempt)
+		log.Printf("Retrying %s %s in %v (retry %d/%d): %v", method, path, delay.Round(time.Millisecond), attempt+1, c.maxRetries, summarizeError(err))
+		if err := sleepRetry(ctx, delay); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// executeRequest performs a single authenticated HTTP request once the rate limiter allows it
+func (c *RestClient) executeRequest(ctx context.Context, method, apiURL string, payload []byte) ([]byte, *http.Response, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, nil, err
+	}
+
 	// Create request
-	req, err := http.NewRequestWithContext(ctx, method, apiURL, nil)
+	var requestBody io.Reader
+	if payload != nil {
+		requestBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, requestBody)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -95,6 +242,9 @@ func (c *RestClient) doRequest(ctx context.Context, method, path string, params
 	// Add authentication header
 	req.Header.Set("PRIVATE-TOKEN", c.token)
 	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	// Execute request
 	resp, err := c.httpClient.Do(req)
@@ -103,6 +253,9 @@ func (c *RestClient) doRequest(ctx context.Context, method, path string, params
 	}
 	defer resp.Body.Close()
 
+	// Let the shared limiter adapt to the remaining rate limit budget
+	c.limiter.Observe(resp.Header)
+
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -111,16 +264,26 @@ func (c *RestClient) doRequest(ctx context.Context, method, path string, params
 
 	// Check for HTTP errors
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return body, resp, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return body, resp, &HTTPError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	return body, resp, nil
 }
 
-// ListProjects implements the GET /projects endpoint or GET /groups/:id/projects for group filtering
-func (c *RestClient) ListProjects(ctx context.Context, options *ListProjectsOptions) ([]*Project, error) {
+// summarizeError shortens an error message for retry logging (response bodies can be large HTML pages)
+func summarizeError(err error) string {
+	msg := err.Error()
+	if len(msg) > 200 {
+		return msg[:200] + "..."
+	}
+	return msg
+}
+
+// ListProjects implements the GET /projects endpoint or GET /groups/:id/projects for group filtering.
+// When keyset pagination is requested but rejected by the server (older GitLab
+// versions), the first page is fetched again with offset pagination.
+func (c *RestClient) ListProjects(ctx context.Context, options *ListProjectsOptions) (*ProjectPage, error) {
 	params := url.Values{}
-	params.Set("page", strconv.Itoa(options.Page))
 	params.Set("per_page", strconv.Itoa(options.PerPage))
 
 	// Key parameters for getting ALL visible projects
@@ -139,6 +302,29 @@ func (c *RestClient) ListProjects(ctx context.Context, options *ListProjectsOpti
 		params.Set("archived", strconv.FormatBool(*options.Archived))
 	}
 
+	// Optional server-side filters
+	if options.Membership != nil && *options.Membership {
+		params.Set("membership", "true")
+	}
+	if options.Owned != nil && *options.Owned {
+		params.Set("owned", "true")
+	}
+	if options.Starred != nil && *options.Starred {
+		params.Set("starred", "true")
+	}
+	if options.Visibility != nil {
+		params.Set("visibility", *options.Visibility)
+	}
+	if options.Search != nil {
+		params.Set("search", *options.Search)
+	}
+	if options.LastActivityAfter != nil {
+		params.Set("last_activity_after", options.LastActivityAfter.UTC().Format(time.RFC3339))
+	}
+	if options.LastActivityBefore != nil {
+		params.Set("last_activity_before", options.LastActivityBefore.UTC().Format(time.RFC3339))
+	}
+
 	// CRITICAL: Use different endpoint when filtering by group ID
 	var endpoint string
 	if options.GroupID != nil {
@@ -146,12 +332,62 @@ func (c *RestClient) ListProjects(ctx context.Context, options *ListProjectsOpti
 		endpoint = fmt.Sprintf("/groups/%d/projects", *options.GroupID)
 		// IMPORTANT: Include projects from subgroups as well
 		params.Set("include_subgroups", "true")
+	} else if options.User != "" {
+		// Personal projects live in the user's namespace, not in a group
+		endpoint = fmt.Sprintf("/users/%s/projects", url.PathEscape(options.User))
 	} else {
 		// Use the general projects endpoint for all visible projects
 		endpoint = "/projects"
 	}
 
-	body, _, err := c.doRequest(ctx, "GET", endpoint, params)
+	// Keyset pagination requires a stable order. Offset pages are ordered the same
+	// way, so pages fetched after a fallback continue where the first one stopped.
+	params.Set("order_by", "id")
+	params.Set("sort", "asc")
+
+	if options.Pagination != "keyset" {
+		return c.listProjectsPage(ctx, endpoint, withProjectPage(params, options.Page))
+	}
+
+	keysetParams := url.Values{}
+	for key, values := range params {
+		keysetParams[key] = values
+	}
+	keysetParams.Set("pagination", "keyset")
+	if options.Cursor != "" {
+		cursor, err := url.ParseQuery(options.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid project cursor %q: %w", options.Cursor, err)
+		}
+		for key, values := range cursor {
+			keysetParams[key] = values
+		}
+	}
+
+	page, err := c.listProjectsPage(ctx, endpoint, keysetParams)
+	if err != nil {
+		if options.Cursor == "" && isStatus(err, http.StatusBadRequest, http.StatusMethodNotAllowed) {
+			log.Printf("Keyset pagination not supported for %s, falling back to offset pagination: %v", endpoint, summarizeError(err))
+			return c.listProjectsPage(ctx, endpoint, withProjectPage(params, 1))
+		}
+		return nil, err
+	}
+	return page, nil
+}
+
+// withProjectPage sets the offset page number on project list parameters
+func withProjectPage(params url.Values, page int) url.Values {
+	params.Set("page", strconv.Itoa(page))
+	return params
+}
+
+// keysetCursorParams are the query parameters GitLab uses to select the next keyset page
+var keysetCursorParams = []string{"cursor", "id_after", "id_before"}
+
+// listProjectsPage fetches a single page of projects. If the response links to a
+// next page using keyset parameters, their values become the page's NextCursor.
+func (c *RestClient) listProjectsPage(ctx context.Context, endpoint string, params url.Values) (*ProjectPage, error) {
+	body, resp, err := c.doRequest(ctx, "GET", endpoint, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list projects: %w", err)
 	}
@@ -163,13 +399,55 @@ func (c *RestClient) ListProjects(ctx context.Context, options *ListProjectsOpti
 	}
 
 	// Convert to our Project type
-	projects := make([]*Project, 0, len(rawProjects))
+	page := &ProjectPage{Projects: make([]*Project, 0, len(rawProjects))}
 	for _, raw := range rawProjects {
 		project := convertRawProject(raw)
-		projects = append(projects, project)
+		page.Projects = append(page.Projects, project)
 	}
 
-	return projects, nil
+	if params.Get("pagination") != "keyset" {
+		return page, nil
+	}
+
+	// Servers without keyset support ignore the parameter and link to page=2 instead,
+	// so the page is only treated as keyset if the next link carries a cursor
+	next, err := url.Parse(nextLink(resp.Header.Get("Link")))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Link header: %w", err)
+	}
+	query := next.Query()
+	cursor := url.Values{}
+	for _, key := range keysetCursorParams {
+		if value := query.Get(key); value != "" {
+			cursor.Set(key, value)
+		}
+	}
+	switch {
+	case len(cursor) > 0:
+		page.Keyset = true
+		page.NextCursor = cursor.Encode()
+	case next.String() == "" && resp.Header.Get("X-Next-Page") == "":
+		page.Keyset = true // Last page; keyset responses carry no Link header there
+	default:
+		log.Printf("Keyset pagination not supported for %s, falling back to offset pagination", endpoint)
+	}
+	return page, nil
+}
+
+// nextLink returns the URL of the rel="next" entry of a Link header, or an empty string
+func nextLink(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+		for _, attr := range parts[1:] {
+			if strings.TrimSpace(attr) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			}
+		}
+	}
+	return ""
 }
 
 // convertRawProject converts a raw JSON map to our Project type
@@ -308,6 +586,9 @@ func (c *RestClient) GetProject(ctx context.Context, projectID interface{}) (*Pr
 	return convertRawProject(raw), nil
 }
 
+// GetProjectStatistics fetches a project with statistics and collects the additional
+// counts that require separate API calls. Counts that could not be retrieved are
+// recorded in FailedMetrics rather than silently reported as zero.
 func (c *RestClient) GetProjectStatistics(ctx context.Context, projectID interface{}) (*ProjectStatistics, error) {
 	// In GitLab API, statistics are included when you get a project with statistics=true
 	// So we'll fetch the project and return its statistics
@@ -320,164 +601,319 @@ func (c *RestClient) GetProjectStatistics(ctx context.Context, projectID interfa
 		// Return empty statistics if not available
 		project.Statistics = &ProjectStatistics{}
 	}
+	stats := project.Statistics
 
-	// Get additional statistics that aren't included in the basic project response
-	// These require separate API calls
+	c.collectStatistics(ctx, projectID, project.WikiEnabled, stats, nil)
+	return stats, nil
+}
 
-	// Get merge request count
-	mrCount, err := c.getMergeRequestCount(ctx, projectID)
-	if err == nil {
-		project.Statistics.MergeRequestCount = mrCount
-	} else {
-		log.Printf("Warning: Failed to get MR count for project %v: %v", projectID, err)
+// collectStatistics fills in the statistics that aren't included in the basic project
+// response. These require separate API calls, which run concurrently up to
+// projectConcurrency. Metrics listed in skip were already collected elsewhere.
+func (c *RestClient) collectStatistics(ctx context.Context, projectID interface{}, wikiEnabled bool, stats *ProjectStatistics, skip map[string]bool) {
+	tasks := []statisticTask{
+		{"MR_Count", &stats.MergeRequestCount, c.getMergeRequestCount, nil},
+		{"Branch_Count", &stats.BranchCount, c.getBranchCount, nil},
+		{"Protected_Branch_Count", &stats.ProtectedBranchCount, plainCount(func(ctx context.Context, projectID interface{}) (int, error) {
+			protectedBranches, err := c.getProtectedBranches(ctx, projectID)
+			stats.ProtectedBranches = protectedBranches
+			return len(protectedBranches), err
+		}), nil},
+		{"Tag_Count", &stats.TagCount, c.getTagCount, nil},
+		{"Collaborator_Count", &stats.MemberCount, c.getMemberCount, nil},
+		{"Milestone_Count", &stats.MilestoneCount, c.getMilestoneCount, nil},
+		{"Release_Count", &stats.ReleaseCount, c.getReleaseCount, nil},
+		// Comment counts and review counts (these are more expensive operations).
+		// A single pass over the MRs fills every MR activity metric.
+		{"MR_Review_Comment_Count", &stats.MergeRequestCommentCount, func(ctx context.Context, projectID interface{}) (countResult, error) {
+			return c.getMergeRequestActivity(ctx, projectID, stats)
+		}, c.mergeRequestActivityMetrics()},
+		{"Issue_Comment_Count", &stats.IssueCommentCount, c.getIssueCommentCount, nil},
 	}
 
-	// Get branch count
-	branchCount, err := c.getBranchCount(ctx, projectID)
-	if err == nil {
-		project.Statistics.BranchCount = branchCount
-	} else {
-		log.Printf("Warning: Failed to get branch count for project %v: %v", projectID, err)
+	// Check if wiki actually has pages (only if wiki is enabled in settings)
+	hasWikiPages := 0
+	if wikiEnabled {
+		tasks = append(tasks, statisticTask{"Has_Wiki", &hasWikiPages, plainCount(c.countWikiPages), nil})
 	}
 
-	// Get tag count
-	tagCount, err := c.getTagCount(ctx, projectID)
-	if err == nil {
-		project.Statistics.TagCount = tagCount
-	} else {
-		log.Printf("Warning: Failed to get tag count for project %v: %v", projectID, err)
+	if len(skip) > 0 {
+		remaining := tasks[:0]
+		for _, task := range tasks {
+			if !skip[task.metric] {
+				remaining = append(remaining, task)
+			}
+		}
+		tasks = remaining
 	}
 
-	// Get member count
-	memberCount, err := c.getMemberCount(ctx, projectID)
-	if err == nil {
-		project.Statistics.MemberCount = memberCount
-	} else {
-		log.Printf("Warning: Failed to get member count for project %v: %v", projectID, err)
+	counts := make([]countResult, len(tasks))
+	errs := make([]error, len(tasks))
+	c.forEachConcurrently(len(tasks), c.projectConcurrency, func(i int) {
+		counts[i], errs[i] = tasks[i].fetch(ctx, projectID)
+	})
+
+	// Record results in a fixed order so the metric lists are deterministic
+	for i, task := range tasks {
+		metrics := append([]string{task.metric}, task.related...)
+		if errs[i] != nil {
+			log.Printf("Warning: Failed to get %s for project %v: %v", strings.Join(metrics, ", "), projectID, errs[i])
+			stats.FailedMetrics = append(stats.FailedMetrics, metrics...)
+			continue
+		}
+		*task.target = counts[i].Value
+		if counts[i].Estimated {
+			stats.EstimatedMetrics = append(stats.EstimatedMetrics, metrics...)
+		}
+		if counts[i].Truncated {
+			stats.TruncatedMetrics = append(stats.TruncatedMetrics, metrics...)
+		}
 	}
-
-	// Get milestone count
-	milestoneCount, err := c.getMilestoneCount(ctx, projectID)
-	if err == nil {
-		project.Statistics.MilestoneCount = milestoneCount
-	} else {
-		log.Printf("Warning: Failed to get milestone count for project %v: %v", projectID, err)
+	if !skip["Has_Wiki"] {
+		stats.HasWikiPages = hasWikiPages > 0
 	}
+}
 
-	// Get release count
-	releaseCount, err := c.getReleaseCount(ctx, projectID)
-	if err == nil {
-		project.Statistics.ReleaseCount = releaseCount
-	} else {
-		log.Printf("Warning: Failed to get release count for project %v: %v", projectID, err)
+// mergeRequestActivityMetrics returns the metrics filled in alongside MR_Review_Comment_Count
+// by the single merge request traversal
+func (c *RestClient) mergeRequestActivityMetrics() []string {
+	metrics := []string{
+		"MR_Review_Count",
+		"MR_Open_Count",
+		"MR_Merged_Count",
+		"MR_Closed_Count",
+		"MR_Draft_Count",
+		"MR_Avg_Merge_Hours",
+		"MR_Median_Merge_Hours",
 	}
-
-	// Check if wiki actually has pages (only if wiki is enabled in settings)
-	if project.WikiEnabled {
-		project.Statistics.HasWikiPages = c.hasWikiPages(ctx, projectID)
-	} else {
-		project.Statistics.HasWikiPages = false
+	if c.reviewDetails {
+		metrics = append(metrics, "MR_Discussion_Count", "MR_Diff_Note_Count")
 	}
+	return metrics
+}
 
-	// Get comment counts and review counts (these are more expensive operations)
-	// Get merge request review count
-	mrReviewCount, err := c.getMergeRequestReviewCount(ctx, projectID)
-	if err == nil {
-		project.Statistics.MergeRequestReviewCount = mrReviewCount
-	} else {
-		log.Printf("Warning: Failed to get MR review count for project %v: %v", projectID, err)
+// countResult is a count collected from the API along with how reliable it is
+type countResult struct {
+	Value     int
+	Estimated bool // Value is a lower bound because the exact total could not be determined
+	Truncated bool // Value only covers the first pages because the page limit was reached
+}
+
+// statisticTask describes a single count collected by a separate API call
+type statisticTask struct {
+	metric  string // CSV column name, used when reporting failures
+	target  *int
+	fetch   func(ctx context.Context, projectID interface{}) (countResult, error)
+	related []string // Further metrics filled in by fetch that share its outcome
+}
+
+// plainCount adapts a function returning an exact count to a statistic task fetch function
+func plainCount(fetch func(ctx context.Context, projectID interface{}) (int, error)) func(context.Context, interface{}) (countResult, error) {
+	return func(ctx context.Context, projectID interface{}) (countResult, error) {
+		count, err := fetch(ctx, projectID)
+		return countResult{Value: count}, err
 	}
+}
 
-	// Get merge request comment count
-	mrCommentCount, err := c.getMergeRequestCommentCount(ctx, projectID)
-	if err == nil {
-		project.Statistics.MergeRequestCommentCount = mrCommentCount
-	} else {
-		log.Printf("Warning: Failed to get MR comment count for project %v: %v", projectID, err)
+// forEachConcurrently calls fn for every index in [0, n) using at most concurrency goroutines
+func (c *RestClient) forEachConcurrently(n, concurrency int, fn func(i int)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
 	}
+	wg.Wait()
+}
 
-	// Get issue comment count
-	issueCommentCount, err := c.getIssueCommentCount(ctx, projectID)
-	if err == nil {
-		project.Statistics.IssueCommentCount = issueCommentCount
-	} else {
-		log.Printf("Warning: Failed to get issue comment count for project %v: %v", projectID, err)
+// getCountFromHeader makes a minimal API request and returns the count from X-Total header.
+// GitLab omits X-Total for collections larger than 10,000 items; in that case the
+// count is determined by searching for the last page instead.
+func (c *RestClient) getCountFromHeader(ctx context.Context, endpoint string, extraParams url.Values) (countResult, error) {
+	params := withPage(extraParams, 1, 1)
+
+	_, resp, err := c.doRequest(ctx, "GET", endpoint, params)
+	if err != nil {
+		return countResult{}, err
 	}
 
-	return project.Statistics, nil
+	if totalHeader := resp.Header.Get("X-Total"); totalHeader != "" {
+		total, err := strconv.Atoi(totalHeader)
+		if err != nil {
+			return countResult{}, fmt.Errorf("invalid X-Total header %q: %w", totalHeader, err)
+		}
+		return countResult{Value: total}, nil
+	}
+	return c.countByPaging(ctx, endpoint, extraParams)
 }
 
-// getCountFromHeader makes a minimal API request and returns the count from X-Total header
-func (c *RestClient) getCountFromHeader(ctx context.Context, endpoint string, extraParams url.Values) int {
-	params := url.Values{}
-	params.Set("per_page", "1")
-	params.Set("page", "1")
-	for key, values := range extraParams {
-		for _, value := range values {
-			params.Add(key, value)
+// countByPaging counts the items of a collection without relying on X-Total. It
+// doubles the page number until it finds an empty page, then binary searches for
+// the last non-empty one, so a collection of n items takes about 2*log2(n/100)
+// requests. If a page beyond MaxCountPages would be needed, or a request fails
+// part-way, the count found so far is returned as a lower bound.
+func (c *RestClient) countByPaging(ctx context.Context, endpoint string, extraParams url.Values) (countResult, error) {
+	pageLength := func(page int) (int, error) {
+		body, _, err := c.doRequest(ctx, "GET", endpoint, withPage(extraParams, page, DefaultPageSize))
+		if err != nil {
+			return 0, err
 		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(body, &items); err != nil {
+			return 0, fmt.Errorf("failed to parse page %d of %s: %w", page, endpoint, err)
+		}
+		return len(items), nil
 	}
 
-	_, resp, err := c.doRequest(ctx, "GET", endpoint, params)
-	if err != nil {
-		return 0
+	// full is the highest page known to be full, empty the lowest page known to be empty
+	full, empty := 0, 0
+	lowerBound := func(err error) (countResult, error) {
+		if full == 0 || ctx.Err() != nil {
+			return countResult{}, err
+		}
+		log.Printf("Warning: Counting %s stopped at page %d, reporting a lower bound: %v", endpoint, full, err)
+		return countResult{Value: full * DefaultPageSize, Estimated: true}, nil
 	}
 
-	if totalHeader := resp.Header.Get("X-Total"); totalHeader != "" {
-		if total, err := strconv.Atoi(totalHeader); err == nil {
-			return total
+	for page := 1; empty == 0; page *= 2 {
+		if page > MaxCountPages {
+			return lowerBound(fmt.Errorf("more than %d pages", MaxCountPages))
+		}
+		n, err := pageLength(page)
+		if err != nil {
+			return lowerBound(err)
+		}
+		switch {
+		case n >= DefaultPageSize:
+			full = page
+		case n == 0:
+			empty = page
+		default:
+			return countResult{Value: (page-1)*DefaultPageSize + n}, nil
 		}
 	}
-	return 0
+
+	for empty-full > 1 {
+		page := (full + empty) / 2
+		n, err := pageLength(page)
+		if err != nil {
+			return lowerBound(err)
+		}
+		switch {
+		case n >= DefaultPageSize:
+			full = page
+		case n == 0:
+			empty = page
+		default:
+			return countResult{Value: (page-1)*DefaultPageSize + n}, nil
+		}
+	}
+
+	return countResult{Value: full * DefaultPageSize}, nil
+}
+
+// withPage returns a copy of params with the given page and page size set
+func withPage(params url.Values, page, perPage int) url.Values {
+	paged := url.Values{}
+	for key, values := range params {
+		paged[key] = append([]string(nil), values...)
+	}
+	paged.Set("page", strconv.Itoa(page))
+	paged.Set("per_page", strconv.Itoa(perPage))
+	return paged
 }
 
 // getMergeRequestCount gets the total count of merge requests for a project
-func (c *RestClient) getMergeRequestCount(ctx context.Context, projectID interface{}) (int, error) {
+func (c *RestClient) getMergeRequestCount(ctx context.Context, projectID interface{}) (countResult, error) {
 	params := url.Values{}
 	params.Set("scope", "all")
 
 	encodedProjectID := c.encodeProjectID(projectID)
 	endpoint := fmt.Sprintf("/projects/%s/merge_requests", encodedProjectID)
-	return c.getCountFromHeader(ctx, endpoint, params), nil
+	return c.getCountFromHeader(ctx, endpoint, params)
 }
 
 // getBranchCount gets the total count of branches for a project
-func (c *RestClient) getBranchCount(ctx context.Context, projectID interface{}) (int, error) {
+func (c *RestClient) getBranchCount(ctx context.Context, projectID interface{}) (countResult, error) {
 	encodedProjectID := c.encodeProjectID(projectID)
 	endpoint := fmt.Sprintf("/projects/%s/repository/branches", encodedProjectID)
-	return c.getCountFromHeader(ctx, endpoint, nil), nil
+	return c.getCountFromHeader(ctx, endpoint, nil)
+}
+
+// getProtectedBranches lists all branch protection rules for a project
+func (c *RestClient) getProtectedBranches(ctx context.Context, projectID interface{}) ([]*ProtectedBranch, error) {
+	params := url.Values{}
+	params.Set("per_page", strconv.Itoa(DefaultPageSize))
+
+	encodedProjectID := c.encodeProjectID(projectID)
+	path := fmt.Sprintf("/projects/%s/protected_branches", encodedProjectID)
+
+	var protectedBranches []*ProtectedBranch
+	for page := 1; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+		body, _, err := c.doRequest(ctx, "GET", path, params)
+		if err != nil {
+			return nil, err
+		}
+
+		var pageBranches []*ProtectedBranch
+		if err := json.Unmarshal(body, &pageBranches); err != nil {
+			return nil, fmt.Errorf("failed to parse protected branches: %w", err)
+		}
+		protectedBranches = append(protectedBranches, pageBranches...)
+
+		if len(pageBranches) < DefaultPageSize {
+			break
+		}
+	}
+
+	return protectedBranches, nil
 }
 
 // getTagCount gets the total count of tags for a project
-func (c *RestClient) getTagCount(ctx context.Context, projectID interface{}) (int, error) {
+func (c *RestClient) getTagCount(ctx context.Context, projectID interface{}) (countResult, error) {
 	encodedProjectID := c.encodeProjectID(projectID)
 	endpoint := fmt.Sprintf("/projects/%s/repository/tags", encodedProjectID)
-	return c.getCountFromHeader(ctx, endpoint, nil), nil
+	return c.getCountFromHeader(ctx, endpoint, nil)
 }
 
 // getMemberCount gets the total count of members for a project
-func (c *RestClient) getMemberCount(ctx context.Context, projectID interface{}) (int, error) {
+func (c *RestClient) getMemberCount(ctx context.Context, projectID interface{}) (countResult, error) {
 	encodedProjectID := c.encodeProjectID(projectID)
 	endpoint := fmt.Sprintf("/projects/%s/members/all", encodedProjectID)
-	return c.getCountFromHeader(ctx, endpoint, nil), nil
+	return c.getCountFromHeader(ctx, endpoint, nil)
 }
 
 // getMilestoneCount gets the total count of milestones for a project
-func (c *RestClient) getMilestoneCount(ctx context.Context, projectID interface{}) (int, error) {
+func (c *RestClient) getMilestoneCount(ctx context.Context, projectID interface{}) (countResult, error) {
 	encodedProjectID := c.encodeProjectID(projectID)
 	endpoint := fmt.Sprintf("/projects/%s/milestones", encodedProjectID)
-	return c.getCountFromHeader(ctx, endpoint, nil), nil
+	return c.getCountFromHeader(ctx, endpoint, nil)
 }
 
 // getReleaseCount gets the total count of releases for a project
-func (c *RestClient) getReleaseCount(ctx context.Context, projectID interface{}) (int, error) {
+func (c *RestClient) getReleaseCount(ctx context.Context, projectID interface{}) (countResult, error) {
 	encodedProjectID := c.encodeProjectID(projectID)
 	endpoint := fmt.Sprintf("/projects/%s/releases", encodedProjectID)
-	return c.getCountFromHeader(ctx, endpoint, nil), nil
+	return c.getCountFromHeader(ctx, endpoint, nil)
+}
+
+// countWikiPages returns 1 if a project actually has wiki pages and 0 otherwise
+func (c *RestClient) countWikiPages(ctx context.Context, projectID interface{}) (int, error) {
+	hasPages, err := c.hasWikiPages(ctx, projectID)
+	if hasPages {
+		return 1, err
+	}
+	return 0, err
 }
 
 // hasWikiPages checks if a project actually has wiki pages
-func (c *RestClient) hasWikiPages(ctx context.Context, projectID interface{}) bool {
+func (c *RestClient) hasWikiPages(ctx context.Context, projectID interface{}) (bool, error) {
 	params := url.Values{}
 	params.Set("per_page", "1")
 	params.Set("page", "1")
@@ -486,160 +922,88 @@ func (c *RestClient) hasWikiPages(ctx context.Context, projectID interface{}) bo
 	path := fmt.Sprintf("/projects/%s/wikis", encodedProjectID)
 	body, _, err := c.doRequest(ctx, "GET", path, params)
 	if err != nil {
-		// If we get an error, assume no wiki (could be disabled or no access)
-		return false
+		// A 403/404 means the wiki is disabled or inaccessible, which we treat as no wiki
+		if isStatus(err, http.StatusForbidden, http.StatusNotFound) {
+			return false, nil
+		}
+		return false, err
 	}
 
 	// Parse the response to see if there are any wiki pages
 	var wikis []map[string]interface{}
 	if err := json.Unmarshal(body, &wikis); err != nil {
-		return false
+		return false, fmt.Errorf("failed to parse wiki response: %w", err)
 	}
 
-	return len(wikis) > 0
+	return len(wikis) > 0, nil
 }
 
-// getMergeRequestReviewCount gets the total count of MR approvals/reviews
-func (c *RestClient) getMergeRequestReviewCount(ctx context.Context, projectID interface{}) (int, error) {
-	// In GitLab, reviews are tracked as "approvals" on merge requests
-	// We'll count MRs that have at least one approval
-	totalReviews := 0
-	mrParams := url.Values{}
-	mrParams.Set("scope", "all")
-	mrParams.Set("per_page", strconv.Itoa(DefaultPageSize))
-
-	encodedProjectID := c.encodeProjectID(projectID)
-	for page := 1; page <= MaxPagesPerQuery; page++ { // Limit to MaxPagesPerQuery * DefaultPageSize MRs
-		mrParams.Set("page", strconv.Itoa(page))
-		mrPath := fmt.Sprintf("/projects/%s/merge_requests", encodedProjectID)
-		mrBody, _, err := c.doRequest(ctx, "GET", mrPath, mrParams)
-		if err != nil {
-			break
-		}
-
-		var pageMRs []map[string]interface{}
-		if err := json.Unmarshal(mrBody, &pageMRs); err != nil {
-			break
-		}
-
-		if len(pageMRs) == 0 {
-			break
-		}
-
-		for _, mr := range pageMRs {
-			// Only count actual approvals from approved_by, not upvotes
-			// Upvotes are just "thumbs up" reactions, not actual code reviews
-			if approvers, ok := mr["approved_by"].([]interface{}); ok {
-				totalReviews += len(approvers)
-			}
-		}
-
-		if len(pageMRs) < DefaultPageSize {
-			break
-		}
-	}
-
-	return totalReviews, nil
-}
-
-// getMergeRequestCommentCount gets the total count of comments on merge requests
-func (c *RestClient) getMergeRequestCommentCount(ctx context.Context, projectID interface{}) (int, error) {
-	// In GitLab, MR comments are called "notes" and include both regular comments and code review comments
-	// We need to get notes from the merge_requests endpoint
+// getIssueCommentCount gets the total count of comments on issues
+func (c *RestClient) getIssueCommentCount(ctx context.Context, projectID interface{}) (countResult, error) {
+	// Similar to MR comments, we need to fetch issues and sum up their notes
 	params := url.Values{}
 	params.Set("scope", "all")
-	params.Set("per_page", "1")
-	params.Set("page", "1")
-
-	encodedProjectID := c.encodeProjectID(projectID)
-	path := fmt.Sprintf("/projects/%s/merge_requests", encodedProjectID)
-	body, _, err := c.doRequest(ctx, "GET", path, params)
-	if err != nil {
-		return 0, err
-	}
-
-	// Parse merge requests to get their IDs, then count notes
-	var mrs []map[string]interface{}
-	if err := json.Unmarshal(body, &mrs); err != nil {
-		return 0, err
-	}
-
-	// For now, we'll use the user_notes_count field from MRs
-	// This requires fetching all MRs to sum up the notes
-	// To avoid excessive API calls, we limit to MaxPagesPerQuery pages
-	totalNotes := 0
-	mrParams := url.Values{}
-	mrParams.Set("scope", "all")
-	mrParams.Set("per_page", strconv.Itoa(DefaultPageSize))
-	for page := 1; page <= MaxPagesPerQuery; page++ { // Limit to MaxPagesPerQuery * DefaultPageSize MRs
-		mrParams.Set("page", strconv.Itoa(page))
-		mrPath := fmt.Sprintf("/projects/%s/merge_requests", encodedProjectID)
-		mrBody, _, err := c.doRequest(ctx, "GET", mrPath, mrParams)
-		if err != nil {
-			break
-		}
-
-		var pageMRs []map[string]interface{}
-		if err := json.Unmarshal(mrBody, &pageMRs); err != nil {
-			break
-		}
-
-		if len(pageMRs) == 0 {
-			break
-		}
 
-		for _, mr := range pageMRs {
-			if userNotesCount, ok := mr["user_notes_count"].(float64); ok {
-				totalNotes += int(userNotesCount)
-			}
-		}
+	path := fmt.Sprintf("/projects/%s/issues", c.encodeProjectID(projectID))
+	return c.sumPages(ctx, path, params, userNotesCount)
+}
 
-		if len(pageMRs) < DefaultPageSize {
-			break
-		}
+// userNotesCount returns the number of user comments on an MR or issue
+func userNotesCount(item map[string]interface{}) int {
+	if count, ok := item["user_notes_count"].(float64); ok {
+		return int(count)
 	}
-
-	return totalNotes, nil
+	return 0
 }
 
-// getIssueCommentCount gets the total count of comments on issues
-func (c *RestClient) getIssueCommentCount(ctx context.Context, projectID interface{}) (int, error) {
-	// Similar to MR comments, we need to fetch issues and sum up their notes
-	totalNotes := 0
-	issueParams := url.Values{}
-	issueParams.Set("scope", "all")
-	issueParams.Set("per_page", strconv.Itoa(DefaultPageSize))
-
-	encodedProjectID := c.encodeProjectID(projectID)
-	for page := 1; page <= MaxPagesPerQuery; page++ { // Limit to MaxPagesPerQuery * DefaultPageSize issues
-		issueParams.Set("page", strconv.Itoa(page))
-		issuePath := fmt.Sprintf("/projects/%s/issues", encodedProjectID)
-		issueBody, _, err := c.doRequest(ctx, "GET", issuePath, issueParams)
-		if err != nil {
-			break
+// sumPages walks the pages of a collection and adds up value for every item
+func (c *RestClient) sumPages(ctx context.Context, endpoint string, params url.Values, value func(item map[string]interface{}) int) (countResult, error) {
+	result := countResult{}
+	truncated, err := c.walkPages(ctx, endpoint, params, func(body []byte) (int, error) {
+		var items []map[string]interface{}
+		if err := json.Unmarshal(body, &items); err != nil {
+			return 0, fmt.Errorf("failed to parse %s: %w", endpoint, err)
 		}
-
-		var pageIssues []map[string]interface{}
-		if err := json.Unmarshal(issueBody, &pageIssues); err != nil {
-			break
+		for _, item := range items {
+			result.Value += value(item)
 		}
+		return len(items), nil
+	})
+	result.Truncated = truncated
+	return result, err
+}
 
-		if len(pageIssues) == 0 {
-			break
+// walkPages fetches the pages of a collection in order, passing each response body
+// to visit, which returns the number of items on the page. At most maxPages pages
+// are fetched (0 walks every page); it reports whether pages remained when the
+// limit was reached.
+func (c *RestClient) walkPages(ctx context.Context, endpoint string, params url.Values, visit func(body []byte) (int, error)) (bool, error) {
+	for page := 1; c.maxPages == 0 || page <= c.maxPages; page++ {
+		body, resp, err := c.doRequest(ctx, "GET", endpoint, withPage(params, page, DefaultPageSize))
+		if err != nil {
+			return false, err
 		}
 
-		for _, issue := range pageIssues {
-			if userNotesCount, ok := issue["user_notes_count"].(float64); ok {
-				totalNotes += int(userNotesCount)
-			}
+		n, err := visit(body)
+		if err != nil {
+			return false, err
 		}
 
-		if len(pageIssues) < DefaultPageSize {
-			break
+		if !hasNextPage(resp, n) {
+			return false, nil
 		}
 	}
+	return true, nil
+}
 
-	return totalNotes, nil
+// hasNextPage reports whether another page follows the one in resp. GitLab's
+// X-Next-Page header is used when present; otherwise a full page is assumed to
+// have a successor.
+func hasNextPage(resp *http.Response, pageLength int) bool {
+	if _, ok := resp.Header["X-Next-Page"]; ok {
+		return resp.Header.Get("X-Next-Page") != ""
+	}
+	return pageLength >= DefaultPageSize
 }
 
 // GetGroupByPath retrieves a group by its full path (e.g., "mygroup" or "mygroup/subgroup")
@@ -0,0 +1,354 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+// Ways a fake server answers keyset pagination requests
+const (
+	keysetSupported = "supported" // Serves keyset pages linked by id_after
+	keysetRejected  = "rejected"  // Responds with 400 Bad Request
+	keysetIgnored   = "ignored"   // Serves offset pages, like GitLab versions without keyset support
+)
+
+// newProjectServer starts a fake GitLab serving count projects with IDs 1..count.
+// Like GitLab, offset pages are ordered by creation date, newest first, unless
+// order_by=id is requested.
+func newProjectServer(t *testing.T, count int, keyset string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		perPage, _ := strconv.Atoi(query.Get("per_page"))
+		if perPage == 0 {
+			perPage = 20
+		}
+
+		ids := make([]int, count)
+		for i := range ids {
+			ids[i] = i + 1
+		}
+		if query.Get("order_by") != "id" {
+			sort.Sort(sort.Reverse(sort.IntSlice(ids)))
+		}
+
+		if query.Get("pagination") == "keyset" && keyset == keysetRejected {
+			http.Error(w, `{"error":"pagination does not have a valid value"}`, http.StatusBadRequest)
+			return
+		}
+		if query.Get("pagination") == "keyset" && keyset == keysetSupported {
+			after, _ := strconv.Atoi(query.Get("id_after"))
+			var page []int
+			for _, id := range ids {
+				if id > after && len(page) < perPage {
+					page = append(page, id)
+				}
+			}
+			if len(page) == perPage && page[len(page)-1] < count {
+				next := *r.URL
+				q := next.Query()
+				q.Set("id_after", strconv.Itoa(page[len(page)-1]))
+				next.RawQuery = q.Encode()
+				w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, r.Host, next.String()))
+			}
+			writeProjects(t, w, page)
+			return
+		}
+
+		pageNumber, _ := strconv.Atoi(query.Get("page"))
+		if pageNumber == 0 {
+			pageNumber = 1
+		}
+		start := min((pageNumber-1)*perPage, count)
+		end := min(start+perPage, count)
+		w.Header().Set("X-Page", strconv.Itoa(pageNumber))
+		w.Header().Set("X-Next-Page", "")
+		if end < count {
+			w.Header().Set("X-Next-Page", strconv.Itoa(pageNumber+1))
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?page=%d>; rel="next"`, r.Host, r.URL.Path, pageNumber+1))
+		}
+		writeProjects(t, w, ids[start:end])
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// writeProjects writes a JSON list of projects with the given IDs
+func writeProjects(t *testing.T, w http.ResponseWriter, ids []int) {
+	projects := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		projects = append(projects, map[string]interface{}{"id": id, "path_with_namespace": fmt.Sprintf("group/project-%d", id)})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(projects); err != nil {
+		t.Errorf("failed to write projects: %v", err)
+	}
+}
+
+func newTestClient(t *testing.T, baseURL string) *RestClient {
+	t.Helper()
+	client, err := NewRestClient(baseURL, "token", nil)
+	if err != nil {
+		t.Fatalf("NewRestClient: %v", err)
+	}
+	return client
+}
+
+func TestListProjectsPagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		count      int
+		keyset     string
+		wantKeyset bool
+	}{
+		{"keyset empty", 0, keysetSupported, true},
+		{"keyset partial page", 50, keysetSupported, true},
+		{"keyset full last page", 200, keysetSupported, true},
+		{"keyset several pages", 250, keysetSupported, true},
+		{"rejected keyset", 250, keysetRejected, false},
+		{"ignored keyset", 250, keysetIgnored, false},
+		{"ignored keyset full last page", 200, keysetIgnored, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, newProjectServer(t, tt.count, tt.keyset).URL)
+			options := &ListProjectsOptions{Page: 1, PerPage: DefaultPageSize, Pagination: "keyset"}
+
+			// Page the way discovery does: follow cursors while the server serves keyset
+			// pages, otherwise continue with offset pages until a short page
+			var ids []int
+			for pageNumber := 1; pageNumber <= 10; pageNumber++ {
+				page, err := client.ListProjects(context.Background(), options)
+				if err != nil {
+					t.Fatalf("ListProjects page %d: %v", pageNumber, err)
+				}
+				if pageNumber == 1 && page.Keyset != tt.wantKeyset {
+					t.Fatalf("first page Keyset = %v, want %v", page.Keyset, tt.wantKeyset)
+				}
+				for _, project := range page.Projects {
+					ids = append(ids, project.ID)
+				}
+				if page.Keyset {
+					if page.NextCursor == "" {
+						break
+					}
+					options.Cursor = page.NextCursor
+					continue
+				}
+				if len(page.Projects) < options.PerPage {
+					break
+				}
+				options.Pagination = ""
+				options.Page = pageNumber + 1
+			}
+
+			if len(ids) != tt.count {
+				t.Fatalf("listed %d projects, want %d", len(ids), tt.count)
+			}
+			for i, id := range ids {
+				if id != i+1 {
+					t.Fatalf("project %d has ID %d, want %d", i, id, i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"empty", "", ""},
+		{"next only", `<https://gitlab.example.com/api/v4/projects?id_after=42>; rel="next"`, "https://gitlab.example.com/api/v4/projects?id_after=42"},
+		{
+			"several links",
+			`<https://gitlab.example.com/api/v4/projects?page=1>; rel="first", <https://gitlab.example.com/api/v4/projects?page=2>; rel="next", <https://gitlab.example.com/api/v4/projects?page=5>; rel="last"`,
+			"https://gitlab.example.com/api/v4/projects?page=2",
+		},
+		{"no next", `<https://gitlab.example.com/api/v4/projects?page=1>; rel="first"`, ""},
+		{"malformed", `https://gitlab.example.com/api/v4/projects`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextLink(tt.header); got != tt.want {
+				t.Errorf("nextLink(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListProjectsPageCursor(t *testing.T) {
+	tests := []struct {
+		name       string
+		link       string
+		nextPage   string
+		wantKeyset bool
+		wantCursor string
+	}{
+		{"cursor", `<https://gitlab.example.com/api/v4/projects?id_after=100&pagination=keyset>; rel="next"`, "", true, "id_after=100"},
+		{"last keyset page", "", "", true, ""},
+		{"offset link", `<https://gitlab.example.com/api/v4/projects?page=2>; rel="next"`, "2", false, ""},
+		{"offset without link", "", "2", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.link != "" {
+					w.Header().Set("Link", tt.link)
+				}
+				if tt.nextPage != "" {
+					w.Header().Set("X-Next-Page", tt.nextPage)
+				}
+				ids := make([]int, DefaultPageSize)
+				for i := range ids {
+					ids[i] = i + 1
+				}
+				writeProjects(t, w, ids)
+			}))
+			defer server.Close()
+
+			client := newTestClient(t, server.URL)
+			page, err := client.ListProjects(context.Background(), &ListProjectsOptions{PerPage: DefaultPageSize, Pagination: "keyset"})
+			if err != nil {
+				t.Fatalf("ListProjects: %v", err)
+			}
+			if page.Keyset != tt.wantKeyset || page.NextCursor != tt.wantCursor {
+				t.Errorf("got Keyset = %v, NextCursor = %q, want %v, %q", page.Keyset, page.NextCursor, tt.wantKeyset, tt.wantCursor)
+			}
+		})
+	}
+}
+
+// countServer is a fake GitLab collection of count items that sends no X-Total
+// header unless total is set. Page failPage fails, if it is set.
+type countServer struct {
+	*httptest.Server
+	requests int
+}
+
+func newCountServer(t *testing.T, count int, total string, failPage int) *countServer {
+	t.Helper()
+	server := &countServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		if page == failPage {
+			http.Error(w, `{"message":"500 Internal Server Error"}`, http.StatusInternalServerError)
+			return
+		}
+		if total != "" {
+			w.Header().Set("X-Total", total)
+		}
+		n := min(max(count-(page-1)*perPage, 0), perPage)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, "[")
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprint(w, "{}")
+		}
+		fmt.Fprint(w, "]")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCountByPaging(t *testing.T) {
+	for _, count := range []int{0, 1, 99, 100, 101, 250, 1000, 10001, 40001} {
+		t.Run(strconv.Itoa(count), func(t *testing.T) {
+			server := newCountServer(t, count, "", 0)
+			client := newTestClient(t, server.URL)
+			result, err := client.countByPaging(context.Background(), "/projects/1/issues", nil)
+			if err != nil {
+				t.Fatalf("countByPaging: %v", err)
+			}
+			if result.Value != count || result.Estimated {
+				t.Errorf("got %d (estimated %v), want exactly %d", result.Value, result.Estimated, count)
+			}
+			if server.requests > 20 {
+				t.Errorf("took %d requests", server.requests)
+			}
+		})
+	}
+}
+
+func TestCountByPagingLowerBound(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    int
+		failPage int
+		want     int
+		wantErr  bool
+	}{
+		{"beyond max pages", (MaxCountPages + 1) * DefaultPageSize, 0, MaxCountPages * DefaultPageSize, false},
+		{"failure while doubling", 1000, 8, 400, false},
+		{"failure while searching", 1000, 12, 800, false},
+		{"failure on first page", 1000, 1, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, newCountServer(t, tt.count, "", tt.failPage).URL)
+			client.maxRetries = 0
+			result, err := client.countByPaging(context.Background(), "/projects/1/issues", nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %d, want an error", result.Value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("countByPaging: %v", err)
+			}
+			if result.Value != tt.want || !result.Estimated {
+				t.Errorf("got %d (estimated %v), want lower bound %d", result.Value, result.Estimated, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCountFromHeader(t *testing.T) {
+	tests := []struct {
+		name         string
+		count        int
+		total        string
+		want         int
+		wantErr      bool
+		wantRequests int
+	}{
+		{"x-total", 250, "250", 250, false, 1},
+		{"x-total zero", 0, "0", 0, false, 1},
+		{"invalid x-total", 250, "many", 0, true, 1},
+		{"no x-total", 250, "", 250, false, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCountServer(t, tt.count, tt.total, 0)
+			client := newTestClient(t, server.URL)
+			result, err := client.getCountFromHeader(context.Background(), "/projects/1/repository/branches", nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getCountFromHeader error = %v, want error %v", err, tt.wantErr)
+			}
+			if result.Value != tt.want || result.Estimated {
+				t.Errorf("got %d (estimated %v), want exactly %d", result.Value, result.Estimated, tt.want)
+			}
+			if server.requests != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", server.requests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the default number of times a failed request is retried
+	DefaultMaxRetries = 5
+	// RetryBaseDelay is the initial backoff delay, doubled on every retry
+	RetryBaseDelay = 1 * time.Second
+	// RetryMaxDelay caps the exponential backoff delay between retries
+	RetryMaxDelay = 60 * time.Second
+	// RetryMaxServerDelay caps delays requested by the server via Retry-After or RateLimit-Reset
+	RetryMaxServerDelay = 15 * time.Minute
+)
+
+// isRetryableStatus reports whether a response status indicates a transient failure
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay determines how long to wait before the given retry attempt (starting at 0).
+// Server-provided hints (Retry-After, RateLimit-Reset) take precedence over exponential backoff.
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	if resp != nil {
+		if delay, ok := serverRetryDelay(resp); ok {
+			return delay
+		}
+	}
+
+	backoff := RetryBaseDelay << attempt
+	if backoff <= 0 || backoff > RetryMaxDelay {
+		backoff = RetryMaxDelay
+	}
+
+	// Equal jitter: wait between half and the full backoff so that parallel
+	// workers hitting the same limit don't retry in lockstep
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// serverRetryDelay extracts the delay requested by GitLab from the response headers.
+// Retry-After may be either a number of seconds or an HTTP date; RateLimit-Reset is
+// a Unix timestamp and is only honoured for 429 responses.
+func serverRetryDelay(resp *http.Response) (time.Duration, bool) {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return capServerDelay(time.Duration(seconds) * time.Second), true
+		}
+		if t, err := http.ParseTime(retryAfter); err == nil {
+			return capServerDelay(time.Until(t)), true
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if reset := resp.Header.Get("RateLimit-Reset"); reset != "" {
+			if unix, err := strconv.ParseInt(reset, 10, 64); err == nil {
+				return capServerDelay(time.Until(time.Unix(unix, 0))), true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// capServerDelay bounds a server-requested delay to a sane range
+func capServerDelay(delay time.Duration) time.Duration {
+	if delay < 0 {
+		return 0
+	}
+	if delay > RetryMaxServerDelay {
+		return RetryMaxServerDelay
+	}
+	return delay
+}
+
+// retryInterruptKey is the context key of the context that ends retry waits early
+type retryInterruptKey struct{}
+
+// WithRetryInterrupt returns a copy of ctx whose requests give up waiting to be
+// retried once interrupt is done. Unlike cancelling ctx, requests being sent are
+// not aborted, so work in progress can finish without waiting out long backoffs.
+func WithRetryInterrupt(ctx, interrupt context.Context) context.Context {
+	return context.WithValue(ctx, retryInterruptKey{}, interrupt)
+}
+
+// sleepRetry waits before retrying a request, like sleepContext, and also gives
+// up when the retry interrupt of ctx (see WithRetryInterrupt) is done
+func sleepRetry(ctx context.Context, delay time.Duration) error {
+	interrupt, ok := ctx.Value(retryInterruptKey{}).(context.Context)
+	if !ok {
+		return sleepContext(ctx, delay)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-interrupt.Done():
+		return fmt.Errorf("not retried: %w", context.Cause(interrupt))
+	}
+}
+
+// sleepContext waits for the given duration or until the context is cancelled
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
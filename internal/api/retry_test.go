@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		status   int
+		header   map[string]string
+		attempt  int
+		min, max time.Duration
+	}{
+		{"retry-after seconds", 429, map[string]string{"Retry-After": "30"}, 0, 30 * time.Second, 30 * time.Second},
+		{"retry-after zero", 503, map[string]string{"Retry-After": "0"}, 3, 0, 0},
+		{"retry-after capped", 429, map[string]string{"Retry-After": "86400"}, 0, RetryMaxServerDelay, RetryMaxServerDelay},
+		{"retry-after date", 503, map[string]string{"Retry-After": now.Add(2 * time.Minute).UTC().Format(http.TimeFormat)}, 0, 118 * time.Second, 2 * time.Minute},
+		{"retry-after past date", 503, map[string]string{"Retry-After": now.Add(-time.Hour).UTC().Format(http.TimeFormat)}, 0, 0, 0},
+		{"retry-after date capped", 429, map[string]string{"Retry-After": now.Add(24 * time.Hour).UTC().Format(http.TimeFormat)}, 0, RetryMaxServerDelay, RetryMaxServerDelay},
+		{"rate limit reset", 429, map[string]string{"RateLimit-Reset": strconv.FormatInt(now.Add(time.Minute).Unix(), 10)}, 0, 58 * time.Second, time.Minute},
+		{"rate limit reset capped", 429, map[string]string{"RateLimit-Reset": strconv.FormatInt(now.Add(time.Hour).Unix(), 10)}, 0, RetryMaxServerDelay, RetryMaxServerDelay},
+		{"rate limit reset ignored without 429", 503, map[string]string{"RateLimit-Reset": strconv.FormatInt(now.Add(time.Hour).Unix(), 10)}, 0, RetryBaseDelay / 2, RetryBaseDelay},
+		{"invalid retry-after", 503, map[string]string{"Retry-After": "soon"}, 1, RetryBaseDelay, 2 * RetryBaseDelay},
+		{"backoff", 502, nil, 2, 2 * RetryBaseDelay, 4 * RetryBaseDelay},
+		{"backoff capped", 502, nil, 10, RetryMaxDelay / 2, RetryMaxDelay},
+		{"backoff overflow", 502, nil, 80, RetryMaxDelay / 2, RetryMaxDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for key, value := range tt.header {
+				resp.Header.Set(key, value)
+			}
+			if delay := retryDelay(resp, tt.attempt); delay < tt.min || delay > tt.max {
+				t.Errorf("retryDelay = %v, want between %v and %v", delay, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestDoWithRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int // Status of each response; the last one repeats
+		maxRetries   int
+		wantErr      bool
+		wantRequests int
+	}{
+		{"rate limited then ok", []int{429, 200}, 5, false, 2},
+		{"server errors then ok", []int{502, 503, 200}, 5, false, 3},
+		{"gives up after max retries", []int{429}, 3, true, 4},
+		{"retries disabled", []int{503}, 0, true, 1},
+		{"not retryable", []int{404}, 5, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[min(requests, len(tt.statuses)-1)]
+				requests++
+				// Ask for an immediate retry, so the test doesn't wait for backoff
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(status)
+				w.Write([]byte("{}"))
+			}))
+			defer server.Close()
+
+			client := newTestClient(t, server.URL)
+			client.maxRetries = tt.maxRetries
+			_, _, err := client.doRequest(context.Background(), "GET", "/projects/1", nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doRequest error = %v, want error %v", err, tt.wantErr)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestDoWithRetryCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	client := newTestClient(t, server.URL)
+	if _, _, err := client.doRequest(ctx, "GET", "/projects/1", nil); err == nil {
+		t.Fatal("doRequest succeeded, want an error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("retry wait took %v after cancellation", elapsed)
+	}
+}
+
+func TestRetryInterrupt(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		// The interrupt ends the wait for a retry
+		{"retry wait", http.StatusTooManyRequests, true},
+		// A request being sent isn't aborted
+		{"request in flight", http.StatusOK, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interrupt, cancel := context.WithCancel(context.Background())
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				cancel()
+				w.Header().Set("Retry-After", "600")
+				w.WriteHeader(tt.status)
+				w.Write([]byte("{}"))
+			}))
+			defer server.Close()
+
+			ctx := WithRetryInterrupt(context.Background(), interrupt)
+			start := time.Now()
+			_, _, err := newTestClient(t, server.URL).doRequest(ctx, "GET", "/projects/1", nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doRequest error = %v, want error %v", err, tt.wantErr)
+			}
+			if requests != 1 {
+				t.Errorf("made %d requests, want 1", requests)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("took %v after the interrupt", elapsed)
+			}
+		})
+	}
+}
@@ -34,6 +34,7 @@ type ProjectStatistics struct {
 	LFSObjectsSize           int64 `json:"lfs_objects_size"`
 	JobArtifactsSize         int64 `json:"job_artifacts_size"`
 	BranchCount              int   `json:"branch_count,omitempty"`
+	ProtectedBranchCount     int   `json:"-"` // Number of protected branch rules (computed)
 	TagCount                 int   `json:"tag_count,omitempty"`
 	MemberCount              int   `json:"member_count,omitempty"`
 	IssueCount               int   `json:"issue_count,omitempty"`
@@ -44,6 +45,26 @@ type ProjectStatistics struct {
 	MergeRequestReviewCount  int   `json:"-"` // Number of MR reviews/approvals (computed)
 	MergeRequestCommentCount int   `json:"-"` // Total comments on merge requests (computed)
 	IssueCommentCount        int   `json:"-"` // Total comments on issues (computed)
+
+	// Merge request breakdown, derived from the same traversal as MergeRequestCommentCount
+	MergeRequestOpenCount        int     `json:"-"`
+	MergeRequestMergedCount      int     `json:"-"`
+	MergeRequestClosedCount      int     `json:"-"`
+	MergeRequestDraftCount       int     `json:"-"`
+	MergeRequestAvgMergeHours    float64 `json:"-"` // Mean time from creation to merge
+	MergeRequestMedianMergeHours float64 `json:"-"` // Median time from creation to merge
+	MergeRequestDiscussionCount  int     `json:"-"` // Discussion threads (only with review details)
+	MergeRequestDiffNoteCount    int     `json:"-"` // Comments on diff lines (only with review details)
+
+	// ProtectedBranches lists the project's branch protection rules
+	ProtectedBranches []*ProtectedBranch `json:"-"`
+
+	// FailedMetrics lists the metrics (by CSV column name) that could not be retrieved
+	FailedMetrics []string `json:"-"`
+	// EstimatedMetrics lists the metrics (by CSV column name) whose value is a lower bound
+	EstimatedMetrics []string `json:"-"`
+	// TruncatedMetrics lists the metrics (by CSV column name) that stopped at the page limit
+	TruncatedMetrics []string `json:"-"`
 }
 
 // Branch represents a GitLab branch
@@ -53,6 +74,25 @@ type Branch struct {
 	Default   bool   `json:"default"`
 }
 
+// ProtectedBranch represents a GitLab protected branch rule. Name may be an exact
+// branch name or a wildcard pattern such as "release/*".
+type ProtectedBranch struct {
+	ID                        int                  `json:"id"`
+	Name                      string               `json:"name"`
+	PushAccessLevels          []*BranchAccessLevel `json:"push_access_levels"`
+	MergeAccessLevels         []*BranchAccessLevel `json:"merge_access_levels"`
+	AllowForcePush            bool                 `json:"allow_force_push"`
+	CodeOwnerApprovalRequired bool                 `json:"code_owner_approval_required"`
+}
+
+// BranchAccessLevel describes who may push to or merge into a protected branch
+type BranchAccessLevel struct {
+	AccessLevel            int    `json:"access_level"`
+	AccessLevelDescription string `json:"access_level_description"`
+	UserID                 *int   `json:"user_id"`
+	GroupID                *int   `json:"group_id"`
+}
+
 // Tag represents a GitLab tag
 type Tag struct {
 	Name               string `json:"name"`
@@ -78,10 +118,51 @@ type Issue struct {
 
 // MergeRequest represents a GitLab merge request
 type MergeRequest struct {
-	ID    int    `json:"id"`
-	IID   int    `json:"iid"`
-	Title string `json:"title"`
-	State string `json:"state"`
+	ID             int         `json:"id"`
+	IID            int         `json:"iid"`
+	Title          string      `json:"title"`
+	State          string      `json:"state"`
+	Draft          bool        `json:"draft"`
+	WorkInProgress bool        `json:"work_in_progress"`
+	UserNotesCount int         `json:"user_notes_count"`
+	ApprovedBy     []*Approver `json:"approved_by"`
+	CreatedAt      *time.Time  `json:"created_at"`
+	MergedAt       *time.Time  `json:"merged_at"`
+	ClosedAt       *time.Time  `json:"closed_at"`
+}
+
+// MergeRequestApprovals represents the approval state of a merge request
+type MergeRequestApprovals struct {
+	ApprovedBy []*Approver `json:"approved_by"`
+}
+
+// Discussion represents a thread of notes on a merge request. Standalone
+// comments are returned as discussions with IndividualNote set.
+type Discussion struct {
+	ID             string  `json:"id"`
+	IndividualNote bool    `json:"individual_note"`
+	Notes          []*Note `json:"notes"`
+}
+
+// Note represents a comment; Type is "DiffNote" for comments on a diff line
+type Note struct {
+	ID     int    `json:"id"`
+	Type   string `json:"type"`
+	System bool   `json:"system"`
+}
+
+// Approver is an entry of a merge request's approved_by list
+type Approver struct {
+	User *User `json:"user"`
+}
+
+// User represents a GitLab user, either listed or embedded in other resources
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	State    string `json:"state"`
+	IsAdmin  bool   `json:"is_admin"` // Only reported for the current user and to administrators
 }
 
 // Milestone represents a GitLab milestone
@@ -105,3 +186,19 @@ type Group struct {
 	Path     string `json:"path"`
 	FullPath string `json:"full_path"`
 }
+
+// Namespace kinds reported by the namespaces API
+const (
+	NamespaceKindGroup = "group"
+	NamespaceKindUser  = "user"
+)
+
+// Namespace represents a GitLab namespace, which is either a group or a user's
+// personal namespace
+type Namespace struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Kind     string `json:"kind"`
+	FullPath string `json:"full_path"`
+}
@@ -1,52 +1,199 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // RepositoryStats represents the CSV output structure for GitLab projects
 type RepositoryStats struct {
-	Namespace            string     `csv:"Namespace"`
-	RepoName             string     `csv:"Project"`
-	IsEmpty              bool       `csv:"Is_Empty"`
-	IsFork               bool       `csv:"isFork"`
-	IsArchive            bool       `csv:"isArchive"`
-	RepoSizeMB           float64    `csv:"Project_Size(mb)"`
-	LFSSizeMB            float64    `csv:"LFS_Size(mb)"`
-	CollaboratorCount    int        `csv:"Collaborator_Count"`
-	ProtectedBranchCount int        `csv:"Protected_Branch_Count"`
-	MRReviewCount        int        `csv:"MR_Review_Count"`
-	MilestoneCount       int        `csv:"Milestone_Count"`
-	IssueCount           int        `csv:"Issue_Count"`
-	MRCount              int        `csv:"MR_Count"`
-	MRReviewCommentCount int        `csv:"MR_Review_Comment_Count"`
-	CommitCount          int        `csv:"Commit_Count"`
-	IssueCommentCount    int        `csv:"Issue_Comment_Count"`
-	ReleaseCount         int        `csv:"Release_Count"`
-	BranchCount          int        `csv:"Branch_Count"`
-	TagCount             int        `csv:"Tag_Count"`
-	HasWiki              bool       `csv:"Has_Wiki"`
-	FullURL              string     `csv:"Full_URL"`
-	Created              *time.Time `csv:"Created"`
-	LastPush             *time.Time `csv:"Last_Push"`
-	LastUpdate           *time.Time `csv:"Last_Update"`
+	ProjectID            int        `csv:"Project_ID" json:"project_id"`
+	Namespace            string     `csv:"Namespace" json:"namespace"`
+	RepoName             string     `csv:"Project" json:"project"`
+	IsEmpty              bool       `csv:"Is_Empty" json:"is_empty"`
+	IsFork               bool       `csv:"isFork" json:"is_fork"`
+	IsArchive            bool       `csv:"isArchive" json:"is_archive"`
+	RepoSizeMB           float64    `csv:"Project_Size(mb)" json:"project_size_mb"`
+	LFSSizeMB            float64    `csv:"LFS_Size(mb)" json:"lfs_size_mb"`
+	RepoSizeBytes        int64      `csv:"-" json:"project_size_bytes"`
+	LFSSizeBytes         int64      `csv:"-" json:"lfs_size_bytes"`
+	CollaboratorCount    int        `csv:"Collaborator_Count" json:"collaborator_count"`
+	ProtectedBranchCount int        `csv:"Protected_Branch_Count" json:"protected_branch_count"`
+	MRReviewCount        int        `csv:"MR_Review_Count" json:"mr_review_count"`
+	MilestoneCount       int        `csv:"Milestone_Count" json:"milestone_count"`
+	IssueCount           int        `csv:"Issue_Count" json:"issue_count"`
+	MRCount              int        `csv:"MR_Count" json:"mr_count"`
+	MRReviewCommentCount int        `csv:"MR_Review_Comment_Count" json:"mr_review_comment_count"`
+	MROpenCount          int        `csv:"MR_Open_Count" json:"mr_open_count"`
+	MRMergedCount        int        `csv:"MR_Merged_Count" json:"mr_merged_count"`
+	MRClosedCount        int        `csv:"MR_Closed_Count" json:"mr_closed_count"`
+	MRDraftCount         int        `csv:"MR_Draft_Count" json:"mr_draft_count"`
+	MRAvgMergeHours      float64    `csv:"MR_Avg_Merge_Hours" json:"mr_avg_merge_hours"`
+	MRMedianMergeHours   float64    `csv:"MR_Median_Merge_Hours" json:"mr_median_merge_hours"`
+	MRDiscussionCount    int        `csv:"MR_Discussion_Count" json:"mr_discussion_count"`
+	MRDiffNoteCount      int        `csv:"MR_Diff_Note_Count" json:"mr_diff_note_count"`
+	CommitCount          int        `csv:"Commit_Count" json:"commit_count"`
+	IssueCommentCount    int        `csv:"Issue_Comment_Count" json:"issue_comment_count"`
+	ReleaseCount         int        `csv:"Release_Count" json:"release_count"`
+	BranchCount          int        `csv:"Branch_Count" json:"branch_count"`
+	TagCount             int        `csv:"Tag_Count" json:"tag_count"`
+	HasWiki              bool       `csv:"Has_Wiki" json:"has_wiki"`
+	FullURL              string     `csv:"Full_URL" json:"full_url"`
+	Created              *time.Time `csv:"Created" json:"created,omitempty"`
+	LastPush             *time.Time `csv:"Last_Push" json:"last_push,omitempty"`
+	LastUpdate           *time.Time `csv:"Last_Update" json:"last_update,omitempty"`
+	FailedMetrics        []string   `csv:"Failed_Metrics" json:"failed_metrics,omitempty"`
+	EstimatedMetrics     []string   `csv:"Estimated_Metrics" json:"estimated_metrics,omitempty"`
+	TruncatedMetrics     []string   `csv:"Truncated_Metrics" json:"truncated_metrics,omitempty"`
+
+	ProtectedBranches []*ProtectedBranchRule `csv:"Protected_Branches" json:"protected_branches,omitempty"`
+
+	MigrationComplexity string   `csv:"Migration_Complexity" json:"migration_complexity,omitempty"` // low, medium, high or blocker
+	MigrationWarnings   []string `csv:"Migration_Warnings" json:"migration_warnings,omitempty"`
+}
+
+// ProtectedBranchRule describes a protected branch (or wildcard pattern) and who may push to or merge into it
+type ProtectedBranchRule struct {
+	Name                      string   `json:"name"`
+	PushAccess                []string `json:"push_access"`
+	MergeAccess               []string `json:"merge_access"`
+	AllowForcePush            bool     `json:"allow_force_push"`
+	CodeOwnerApprovalRequired bool     `json:"code_owner_approval_required"`
+}
+
+// ReportMetadata describes the scan that produced a report
+type ReportMetadata struct {
+	GitLabURL         string     `json:"gitlab_url"`
+	StartedAt         time.Time  `json:"started_at"`
+	CompletedAt       time.Time  `json:"completed_at"`
+	DurationSeconds   float64    `json:"duration_seconds"`
+	TotalProjects     int        `json:"total_projects"`
+	ProcessedProjects int        `json:"processed_projects"`
+	ExcludedProjects  int        `json:"excluded_projects"`
+	UnchangedProjects int        `json:"unchanged_projects,omitempty"` // Copied from the baseline report
+	ErrorCount        int        `json:"error_count"`
+	Partial           bool       `json:"partial"`
+	IncrementalSince  *time.Time `json:"incremental_since,omitempty"` // Activity cutoff of an incremental scan
+	MaxPages          *int       `json:"max_pages,omitempty"`         // Page limit of comment and review counts (0 = no limit)
+	MergedFrom        []string   `json:"merged_from,omitempty"`       // Reports combined by the merge command
 }
 
 // ScanOptions represents the options for scanning GitLab
 type ScanOptions struct {
-	GitLabURL    string
-	Token        string
-	GroupID      *int
-	Namespace    string // Namespace/group path for filtering (e.g., "mygroup/subgroup")
-	OutputFormat string
-	OutputFile   string
-	Verbose      bool
-	MaxProjects  int
+	GitLabURL        string
+	Token            string
+	GroupID          *int
+	Namespace        string // Namespace/group path for filtering (e.g., "mygroup/subgroup" or a username)
+	PersonalProjects bool   // List the personal projects of every user instead of a namespace
+	OutputFormat     string
+	OutputFile       string
+	Verbose          bool
+	MaxProjects      int
+	Workers          int // Number of projects scanned in parallel (0 uses the default)
 }
 
 // ScanResult represents the result of a GitLab scan operation
 type ScanResult struct {
 	TotalProjects     int
 	ProcessedProjects int
+	ResumedProjects   int // Projects restored from a checkpoint instead of being rescanned
+	ExcludedProjects  int // Discovered projects dropped by include/exclude filters
+	UnchangedProjects int // Projects kept from the baseline report of an incremental scan
 	RepositoryStats   []*RepositoryStats
 	Errors            []error
 	Duration          time.Duration
+	Interrupted       bool     // The scan was stopped before all projects were processed
+	Unscanned         []string // Paths of projects without statistics when the scan was interrupted; "#" lines are notes
+}
+
+// UnscannedCount returns the number of unscanned projects, ignoring "#" note lines
+func (r *ScanResult) UnscannedCount() int {
+	count := 0
+	for _, line := range r.Unscanned {
+		if !strings.HasPrefix(line, "#") {
+			count++
+		}
+	}
+	return count
+}
+
+// Kinds of ProjectChange
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeRenamed = "renamed"
+	ChangeUpdated = "changed"
+)
+
+// ProjectChange describes how a project differs between two reports
+type ProjectChange struct {
+	Change      string           // ChangeAdded, ChangeRemoved, ChangeRenamed or ChangeUpdated
+	Old         *RepositoryStats // nil for added projects
+	New         *RepositoryStats // nil for removed projects
+	SizeDeltaMB float64
+	LFSDeltaMB  float64
+	CommitDelta int
+	BranchDelta int
+	TagDelta    int
+	Archived    bool // Archived since the old report
+	Unarchived  bool // No longer archived
+}
+
+// ReportDiff is the comparison of two reports
+type ReportDiff struct {
+	OldProjects int
+	NewProjects int
+	Changes     []*ProjectChange // Projects that were added, removed, renamed or changed
+	Unchanged   int              // Projects present in both reports without changes
+}
+
+// Levels of a NamespaceSummary
+const (
+	SummaryGroup     = "group"     // A top-level group or user namespace, including its subgroups
+	SummaryNamespace = "namespace" // The projects directly in one namespace
+	SummaryTotal     = "total"     // Every project of the report
+)
+
+// NamespaceSummary aggregates the statistics of the projects in a namespace
+type NamespaceSummary struct {
+	Level             string     `json:"level"`
+	Namespace         string     `json:"namespace"`
+	ProjectCount      int        `json:"project_count"`
+	ArchivedCount     int        `json:"archived_count"`
+	EmptyCount        int        `json:"empty_count"`
+	ForkCount         int        `json:"fork_count"`
+	TotalSizeMB       float64    `json:"total_size_mb"`
+	AvgSizeMB         float64    `json:"avg_size_mb"`
+	MaxSizeMB         float64    `json:"max_size_mb"`
+	TotalLFSSizeMB    float64    `json:"total_lfs_size_mb"`
+	AvgLFSSizeMB      float64    `json:"avg_lfs_size_mb"`
+	MaxLFSSizeMB      float64    `json:"max_lfs_size_mb"`
+	IssueCount        int        `json:"issue_count"`
+	MRCount           int        `json:"mr_count"`
+	CommitCount       int        `json:"commit_count"`
+	LastActivity      *time.Time `json:"last_activity,omitempty"`
+	LastActiveProject string     `json:"last_active_project,omitempty"` // Path of the most recently active project
+}
+
+// PlannedProject is a project with its estimated migration time and wave
+type PlannedProject struct {
+	Stats          *RepositoryStats
+	EstimatedHours float64
+	Wave           int    // 0 for projects left out of the plan
+	Note           string // Why the project was split from its namespace or left out
+}
+
+// MigrationWave is a set of projects migrated together
+type MigrationWave struct {
+	Number     int
+	Projects   []*PlannedProject
+	Hours      float64
+	Namespaces int // Namespaces with projects in the wave
+}
+
+// MigrationPlan assigns the projects of a report to migration waves
+type MigrationPlan struct {
+	Waves      []*MigrationWave
+	Blocked    []*PlannedProject // Projects with a blocker, which can't be migrated as is
+	TotalHours float64           // Estimated hours of the planned projects
 }
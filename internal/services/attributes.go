@@ -0,0 +1,116 @@
+package services
+
+import (
+	"slices"
+	"time"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/api"
+)
+
+// Archived project selection modes
+const (
+	ArchivedInclude = "include" // Archived and active projects
+	ArchivedOnly    = "only"    // Archived projects only
+	ArchivedExclude = "exclude" // Active projects only
+)
+
+// AttributeFilter selects projects by their attributes. Criteria GitLab can evaluate
+// are sent with the project list requests; all but Owned, Membership, Starred and
+// Search are also checked on every project, which covers endpoints and GitLab
+// versions that ignore a parameter, and projects named by --repo-list. Zero values
+// don't filter, and a nil filter selects every project.
+type AttributeFilter struct {
+	Archived     string    // ArchivedInclude (default), ArchivedOnly or ArchivedExclude
+	Visibility   []string  // Allowed visibility levels (public, internal, private)
+	SkipForks    bool      // Skip projects forked from another project
+	SkipEmpty    bool      // Skip projects without a repository
+	ActiveSince  time.Time // Skip projects without activity since this time
+	ActiveBefore time.Time // Skip projects with activity since this time
+
+	// Only evaluated by GitLab
+	Owned      bool   // Projects owned by the token's user
+	Membership bool   // Projects the token's user is a member of
+	Starred    bool   // Projects starred by the token's user
+	Search     string // Projects whose name or path contains this text
+}
+
+// apply adds the criteria GitLab can evaluate to a project listing
+func (f *AttributeFilter) apply(options *api.ListProjectsOptions) {
+	if f == nil {
+		return
+	}
+
+	switch f.Archived {
+	case ArchivedOnly:
+		archived := true
+		options.Archived = &archived
+	case ArchivedExclude:
+		archived := false
+		options.Archived = &archived
+	}
+
+	// The API accepts a single visibility level; several are filtered client-side
+	if len(f.Visibility) == 1 {
+		options.Visibility = &f.Visibility[0]
+	}
+
+	if !f.ActiveSince.IsZero() {
+		options.LastActivityAfter = &f.ActiveSince
+	}
+	if !f.ActiveBefore.IsZero() {
+		options.LastActivityBefore = &f.ActiveBefore
+	}
+
+	if f.Owned {
+		options.Owned = &f.Owned
+	}
+	if f.Membership {
+		options.Membership = &f.Membership
+	}
+	if f.Starred {
+		options.Starred = &f.Starred
+	}
+	if f.Search != "" {
+		options.Search = &f.Search
+	}
+}
+
+// Match reports whether a project satisfies the criteria that can be checked
+// client-side. Projects without a known last activity are kept.
+func (f *AttributeFilter) Match(project *api.Project) bool {
+	if f == nil {
+		return true
+	}
+
+	switch f.Archived {
+	case ArchivedOnly:
+		if !project.Archived {
+			return false
+		}
+	case ArchivedExclude:
+		if project.Archived {
+			return false
+		}
+	}
+
+	if len(f.Visibility) > 0 && !slices.Contains(f.Visibility, project.Visibility) {
+		return false
+	}
+	if f.SkipForks && project.ForkedFromProject {
+		return false
+	}
+	if f.SkipEmpty && project.EmptyRepo {
+		return false
+	}
+
+	if project.LastActivityAt != nil {
+		if !f.ActiveSince.IsZero() && project.LastActivityAt.Before(f.ActiveSince) {
+			return false
+		}
+		if !f.ActiveBefore.IsZero() && !project.LastActivityAt.Before(f.ActiveBefore) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/api"
+)
+
+func TestAttributeFilterMatch(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	at := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name    string
+		filter  *AttributeFilter
+		project api.Project
+		want    bool
+	}{
+		{"nil filter", nil, api.Project{Archived: true, ForkedFromProject: true}, true},
+		{"empty filter", &AttributeFilter{}, api.Project{Archived: true, EmptyRepo: true}, true},
+		{"archived included", &AttributeFilter{Archived: ArchivedInclude}, api.Project{Archived: true}, true},
+		{"archived only keeps archived", &AttributeFilter{Archived: ArchivedOnly}, api.Project{Archived: true}, true},
+		{"archived only skips active", &AttributeFilter{Archived: ArchivedOnly}, api.Project{}, false},
+		{"archived excluded", &AttributeFilter{Archived: ArchivedExclude}, api.Project{Archived: true}, false},
+		{"active kept", &AttributeFilter{Archived: ArchivedExclude}, api.Project{}, true},
+		{"visibility allowed", &AttributeFilter{Visibility: []string{"internal", "private"}}, api.Project{Visibility: "private"}, true},
+		{"visibility not allowed", &AttributeFilter{Visibility: []string{"internal", "private"}}, api.Project{Visibility: "public"}, false},
+		{"fork skipped", &AttributeFilter{SkipForks: true}, api.Project{ForkedFromProject: true}, false},
+		{"non-fork kept", &AttributeFilter{SkipForks: true}, api.Project{}, true},
+		{"empty skipped", &AttributeFilter{SkipEmpty: true}, api.Project{EmptyRepo: true}, false},
+		{"active since", &AttributeFilter{ActiveSince: since}, api.Project{LastActivityAt: at(since.Add(time.Hour))}, true},
+		{"active exactly at since", &AttributeFilter{ActiveSince: since}, api.Project{LastActivityAt: at(since)}, true},
+		{"inactive since", &AttributeFilter{ActiveSince: since}, api.Project{LastActivityAt: at(since.Add(-time.Hour))}, false},
+		{"active before", &AttributeFilter{ActiveBefore: before}, api.Project{LastActivityAt: at(before.Add(-time.Hour))}, true},
+		{"active exactly at before", &AttributeFilter{ActiveBefore: before}, api.Project{LastActivityAt: at(before)}, false},
+		{"within window", &AttributeFilter{ActiveSince: since, ActiveBefore: before}, api.Project{LastActivityAt: at(since.Add(24 * time.Hour))}, true},
+		{"unknown activity kept", &AttributeFilter{ActiveSince: since, ActiveBefore: before}, api.Project{}, true},
+		{"server-side criteria not checked", &AttributeFilter{Owned: true, Starred: true, Search: "api"}, api.Project{Name: "web"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(&tt.project); got != tt.want {
+				t.Errorf("Match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
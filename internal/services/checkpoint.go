@@ -0,0 +1,134 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+	"github.com/mona-actions/gh-gitlab-stats/internal/ui"
+)
+
+// Checkpoint persists the statistics of completed projects as a scan progresses,
+// one JSON object per line, so that an interrupted scan can be resumed without
+// rescanning projects that were already processed.
+type Checkpoint struct {
+	mu        sync.Mutex
+	path      string
+	file      *os.File
+	completed map[int]*models.RepositoryStats
+}
+
+// OpenCheckpoint opens the checkpoint file at path, loading any previously recorded
+// projects, and creates it if it does not exist yet
+func OpenCheckpoint(path string) (*Checkpoint, error) {
+	completed, err := loadCheckpoint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint file %s: %w", path, err)
+	}
+
+	return &Checkpoint{
+		path:      path,
+		file:      file,
+		completed: completed,
+	}, nil
+}
+
+// loadCheckpoint reads the projects recorded in an existing checkpoint file
+func loadCheckpoint(path string) (map[int]*models.RepositoryStats, error) {
+	completed := make(map[int]*models.RepositoryStats)
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return completed, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var stat models.RepositoryStats
+		if err := json.Unmarshal(line, &stat); err != nil {
+			// The last line may be incomplete if the previous run was killed mid-write;
+			// that project will simply be scanned again
+			fmt.Fprintf(ui.Console, "Warning: Ignoring invalid checkpoint entry on line %d of %s: %v\n", lineNumber, path, err)
+			continue
+		}
+		if stat.ProjectID == 0 {
+			continue
+		}
+		completed[stat.ProjectID] = &stat
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file %s: %w", path, err)
+	}
+
+	return completed, nil
+}
+
+// Path returns the location of the checkpoint file
+func (c *Checkpoint) Path() string {
+	return c.path
+}
+
+// Len returns the number of completed projects in the checkpoint
+func (c *Checkpoint) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.completed)
+}
+
+// Get returns the recorded statistics for a project, if it was already completed
+func (c *Checkpoint) Get(projectID int) (*models.RepositoryStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stat, ok := c.completed[projectID]
+	return stat, ok
+}
+
+// Record appends the statistics of a completed project to the checkpoint file
+func (c *Checkpoint) Record(stat *models.RepositoryStats) error {
+	data, err := json.Marshal(stat)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.file.Write(data); err != nil {
+		return fmt.Errorf("failed to write checkpoint file %s: %w", c.path, err)
+	}
+	c.completed[stat.ProjectID] = stat
+	return nil
+}
+
+// Close closes the checkpoint file, keeping it on disk for a later resume
+func (c *Checkpoint) Close() error {
+	return c.file.Close()
+}
+
+// Remove closes and deletes the checkpoint file once it is no longer needed
+func (c *Checkpoint) Remove() error {
+	if err := c.file.Close(); err != nil {
+		return err
+	}
+	return os.Remove(c.path)
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"math"
+	"sort"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+	"github.com/mona-actions/gh-gitlab-stats/internal/ui"
+)
+
+// DiffReports compares the projects of two reports. Projects are matched by
+// project ID when both reports have one, otherwise by URL; a project matched by
+// ID under a different path is reported as renamed. CSV reports written by older
+// versions carry no IDs, so renamed projects appear as removed and added. CSV
+// sizes are rounded to whole megabytes, so sizes are compared in whole megabytes
+// when either report is a CSV report.
+func DiffReports(oldReport, newReport *ui.Report) *models.ReportDiff {
+	oldStats, newStats := oldReport.Stats, newReport.Stats
+	diff := &models.ReportDiff{OldProjects: len(oldStats), NewProjects: len(newStats)}
+
+	size := func(mb float64) float64 { return mb }
+	if oldReport.Format == "csv" || newReport.Format == "csv" {
+		// Same rounding as the CSV writer's %.0f
+		size = math.RoundToEven
+	}
+
+	byID := make(map[int]*models.RepositoryStats, len(oldStats))
+	byURL := make(map[string]*models.RepositoryStats, len(oldStats))
+	for _, stat := range oldStats {
+		if _, ok := byID[stat.ProjectID]; !ok && stat.ProjectID != 0 {
+			byID[stat.ProjectID] = stat
+		}
+		if _, ok := byURL[stat.FullURL]; !ok && stat.FullURL != "" {
+			byURL[stat.FullURL] = stat
+		}
+	}
+
+	matched := make(map[*models.RepositoryStats]bool, len(oldStats))
+	for _, stat := range newStats {
+		old := byID[stat.ProjectID]
+		if old == nil || stat.ProjectID == 0 {
+			old = byURL[stat.FullURL]
+		}
+		if old == nil || matched[old] {
+			diff.Changes = append(diff.Changes, compareProjects(nil, stat, size))
+			continue
+		}
+		matched[old] = true
+
+		change := compareProjects(old, stat, size)
+		if change == nil {
+			diff.Unchanged++
+			continue
+		}
+		diff.Changes = append(diff.Changes, change)
+	}
+
+	for _, stat := range oldStats {
+		if !matched[stat] {
+			diff.Changes = append(diff.Changes, compareProjects(stat, nil, size))
+		}
+	}
+
+	sort.SliceStable(diff.Changes, func(i, j int) bool {
+		a, b := diff.Changes[i], diff.Changes[j]
+		if changeOrder[a.Change] != changeOrder[b.Change] {
+			return changeOrder[a.Change] < changeOrder[b.Change]
+		}
+		return changePath(a) < changePath(b)
+	})
+	return diff
+}
+
+// changeOrder lists added projects first, then removed, renamed and changed ones
+var changeOrder = map[string]int{
+	models.ChangeAdded:   0,
+	models.ChangeRemoved: 1,
+	models.ChangeRenamed: 2,
+	models.ChangeUpdated: 3,
+}
+
+// compareProjects returns the change between two versions of a project, either
+// of which may be nil, or nil when nothing tracked has changed. Added and removed
+// projects count their whole size, branches and tags as growth or shrinkage. Sizes
+// are compared at the precision given by size.
+func compareProjects(old, current *models.RepositoryStats, size func(mb float64) float64) *models.ProjectChange {
+	before, after := old, current
+	if before == nil {
+		before = &models.RepositoryStats{}
+	}
+	if after == nil {
+		after = &models.RepositoryStats{}
+	}
+
+	change := &models.ProjectChange{
+		Old:         old,
+		New:         current,
+		SizeDeltaMB: size(after.RepoSizeMB) - size(before.RepoSizeMB),
+		LFSDeltaMB:  size(after.LFSSizeMB) - size(before.LFSSizeMB),
+		CommitDelta: after.CommitCount - before.CommitCount,
+		BranchDelta: after.BranchCount - before.BranchCount,
+		TagDelta:    after.TagCount - before.TagCount,
+	}
+
+	switch {
+	case old == nil:
+		change.Change = models.ChangeAdded
+	case current == nil:
+		change.Change = models.ChangeRemoved
+	default:
+		change.Archived = after.IsArchive && !before.IsArchive
+		change.Unarchived = before.IsArchive && !after.IsArchive
+		switch {
+		case projectPath(old) != projectPath(current) || old.FullURL != current.FullURL:
+			change.Change = models.ChangeRenamed
+		case change.SizeDeltaMB != 0 || change.LFSDeltaMB != 0 || change.CommitDelta != 0 ||
+			change.BranchDelta != 0 || change.TagDelta != 0 || change.Archived || change.Unarchived:
+			change.Change = models.ChangeUpdated
+		default:
+			return nil
+		}
+	}
+	return change
+}
+
+// changePath returns the current path of a changed project, or its last path if it was removed
+func changePath(change *models.ProjectChange) string {
+	if change.New != nil {
+		return projectPath(change.New)
+	}
+	return projectPath(change.Old)
+}
+
+// projectPath returns the namespace and name of a project as a single path
+func projectPath(stat *models.RepositoryStats) string {
+	return stat.Namespace + "/" + stat.RepoName
+}
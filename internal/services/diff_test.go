@@ -0,0 +1,130 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+	"github.com/mona-actions/gh-gitlab-stats/internal/ui"
+)
+
+// writeReport writes stats in the given format and reads the report back
+func writeReport(t *testing.T, stats []*models.RepositoryStats, format string) *ui.Report {
+	t.Helper()
+	formatter, err := ui.NewFormatter(format)
+	if err != nil {
+		t.Fatalf("NewFormatter(%s): %v", format, err)
+	}
+	path := filepath.Join(t.TempDir(), "report."+format)
+	if err := formatter.WriteToFile(stats, path); err != nil {
+		t.Fatalf("WriteToFile: %v", err)
+	}
+	report, err := ui.ReadReport(path)
+	if err != nil {
+		t.Fatalf("ReadReport: %v", err)
+	}
+	return report
+}
+
+// diffTestStats returns projects whose sizes aren't whole megabytes, including
+// sizes exactly halfway between two
+func diffTestStats() []*models.RepositoryStats {
+	project := func(id int, name string, sizeMB, lfsMB float64) *models.RepositoryStats {
+		return &models.RepositoryStats{
+			ProjectID:   id,
+			Namespace:   "group",
+			RepoName:    name,
+			FullURL:     "https://gitlab.example.com/group/" + name,
+			RepoSizeMB:  sizeMB,
+			LFSSizeMB:   lfsMB,
+			CommitCount: 10 * id,
+		}
+	}
+	return []*models.RepositoryStats{
+		project(1, "api", 12.34, 0),
+		project(2, "web", 0.5, 2.5),
+		project(3, "docs", 0.04, 0),
+		project(4, "tools", 1023.7, 511.49),
+	}
+}
+
+func TestDiffReportsCSVAgainstJSON(t *testing.T) {
+	csvReport := writeReport(t, diffTestStats(), "csv")
+	jsonReport := writeReport(t, diffTestStats(), "json")
+
+	for _, tt := range []struct {
+		name     string
+		old, new *ui.Report
+	}{
+		{"csv to json", csvReport, jsonReport},
+		{"json to csv", jsonReport, csvReport},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffReports(tt.old, tt.new)
+			for _, change := range diff.Changes {
+				t.Errorf("%s %s: size %+.2f MB, LFS %+.2f MB", change.Change, changePath(change), change.SizeDeltaMB, change.LFSDeltaMB)
+			}
+			if diff.Unchanged != len(diffTestStats()) {
+				t.Errorf("got %d unchanged projects, want %d", diff.Unchanged, len(diffTestStats()))
+			}
+		})
+	}
+}
+
+func TestDiffReportsSizeChanges(t *testing.T) {
+	grown := diffTestStats()
+	grown[0].RepoSizeMB += 2
+	grown[2].RepoSizeMB += 0.3
+
+	tests := []struct {
+		name      string
+		oldFormat string
+		newFormat string
+		want      map[string]float64 // Size delta of each changed project
+	}{
+		{"json", "json", "json", map[string]float64{"group/api": 2, "group/docs": 0.3}},
+		{"csv to json", "csv", "json", map[string]float64{"group/api": 2}},
+		{"json to csv", "json", "csv", map[string]float64{"group/api": 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffReports(writeReport(t, diffTestStats(), tt.oldFormat), writeReport(t, grown, tt.newFormat))
+
+			got := make(map[string]float64)
+			for _, change := range diff.Changes {
+				if change.Change != models.ChangeUpdated {
+					t.Errorf("%s: got %s, want %s", changePath(change), change.Change, models.ChangeUpdated)
+				}
+				got[changePath(change)] = change.SizeDeltaMB
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got changes %v, want %v", got, tt.want)
+			}
+			for path, delta := range tt.want {
+				if diff := got[path] - delta; diff > 1e-9 || diff < -1e-9 {
+					t.Errorf("%s: size delta %v, want %v", path, got[path], delta)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffReportsRenamed(t *testing.T) {
+	renamed := diffTestStats()
+	renamed[1].Namespace = "platform"
+	renamed[1].FullURL = "https://gitlab.example.com/platform/web"
+
+	for _, format := range []string{"csv", "json", "ndjson"} {
+		t.Run(format, func(t *testing.T) {
+			diff := DiffReports(writeReport(t, diffTestStats(), format), writeReport(t, renamed, format))
+			if len(diff.Changes) != 1 {
+				t.Fatalf("got %d changes, want 1", len(diff.Changes))
+			}
+			change := diff.Changes[0]
+			if change.Change != models.ChangeRenamed || changePath(change) != "platform/web" {
+				t.Errorf("got %s %s, want %s platform/web", change.Change, changePath(change), models.ChangeRenamed)
+			}
+		})
+	}
+}
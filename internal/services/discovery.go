@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/api"
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+	"github.com/mona-actions/gh-gitlab-stats/internal/ui"
+)
+
+// discoveryEvent reports the progress of project discovery to the result loop
+type discoveryEvent struct {
+	found      int                       // Projects found on the page
+	excluded   int                       // Projects on the page dropped by the filter
+	restored   []*models.RepositoryStats // Projects on the page restored from the checkpoint
+	unchanged  []*models.RepositoryStats // Projects on the page kept from the baseline report
+	done       bool                      // Discovery ended (completely or not)
+	complete   bool                      // Every project was listed, set when done
+	discovered []*api.Project            // Every project found, set when done
+	err        error                     // Why discovery failed, if it did
+}
+
+// discover lists the projects to scan page by page. Projects already in the
+// checkpoint are reported as restored; the others are sent to pages so workers
+// can start on them while later pages are still being listed. It closes pages
+// and events when discovery ends.
+func (s *Scanner) discover(ctx context.Context, options *models.ScanOptions, pages chan<- []*api.Project, events chan<- discoveryEvent) {
+	defer close(events)
+	defer close(pages)
+
+	var discovered []*api.Project
+	stopped := false
+	err := s.discoverProjects(ctx, options, func(projects []*api.Project, excluded int) bool {
+		discovered = append(discovered, projects...)
+
+		pending, restored := s.restoreFromCheckpoint(projects)
+		pending, unchanged := s.restoreFromBaseline(pending)
+		events <- discoveryEvent{found: len(projects), excluded: excluded, restored: restored, unchanged: unchanged}
+
+		select {
+		case pages <- pending:
+			return true
+		case <-ctx.Done():
+		case <-s.stop:
+		}
+		stopped = true
+		return false
+	})
+	events <- discoveryEvent{done: true, complete: err == nil && !stopped, discovered: discovered, err: err}
+}
+
+// feedProjects queues the pages found by discovery and hands their projects to the
+// workers one at a time, so discovery never waits for workers to catch up. The
+// projects about to be handed over are prefetched a batch at a time, so clients
+// don't hold statistics for projects a stopped scan never reaches. It closes out
+// once every queued project was handed over or the scan is stopped.
+func (s *Scanner) feedProjects(ctx context.Context, pages <-chan []*api.Project, out chan<- *api.Project) {
+	defer close(out)
+
+	var queue []*api.Project
+	prefetched := 0 // Projects at the head of the queue that were prefetched
+	for pages != nil || len(queue) > 0 {
+		// Sending is only enabled while there is something to send
+		var send chan<- *api.Project
+		var next *api.Project
+		if len(queue) > 0 {
+			if prefetched == 0 {
+				prefetched = min(len(queue), api.GraphQLBatchSize)
+				s.prefetch(queue[:prefetched])
+			}
+			send = out
+			next = queue[0]
+		}
+
+		select {
+		case page, ok := <-pages:
+			if !ok {
+				pages = nil
+				continue
+			}
+			queue = append(queue, page...)
+		case send <- next:
+			queue = queue[1:]
+			prefetched--
+		case <-ctx.Done():
+			return
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// discoverProjects lists the projects to scan and passes each page, along with the
+// number of its projects excluded by the filter, to handlePage, stopping early when
+// handlePage returns false
+func (s *Scanner) discoverProjects(ctx context.Context, options *models.ScanOptions, handlePage func(projects []*api.Project, excluded int) bool) error {
+	total := 0
+	excluded := 0
+	duplicates := 0
+	seen := make(map[int]bool)
+
+	// Later scans of the same run skip these projects, so overlapping namespaces and
+	// projects shared with several groups are only scanned once
+	defer func() {
+		for id := range seen {
+			s.listed[id] = true
+		}
+	}()
+
+	// emit drops projects that were already listed (offset pages shift when projects
+	// are created mid-scan, and an earlier namespace may have included them) or that
+	// don't match the filter, and enforces the max projects limit
+	emit := func(projects []*api.Project) bool {
+		fresh := make([]*api.Project, 0, len(projects))
+		pageExcluded := 0
+		for _, project := range projects {
+			if seen[project.ID] {
+				continue
+			}
+			seen[project.ID] = true
+			if s.listed[project.ID] {
+				duplicates++
+				continue
+			}
+			if !s.filter.Match(project.PathWithNamespace) || !s.attributes.Match(project) {
+				pageExcluded++
+				continue
+			}
+			fresh = append(fresh, project)
+		}
+		excluded += pageExcluded
+		if len(fresh) == 0 && pageExcluded == 0 {
+			return true
+		}
+
+		// Check if we've hit the max projects limit
+		last := false
+		if options.MaxProjects > 0 && total+len(fresh) >= options.MaxProjects {
+			fresh = fresh[:options.MaxProjects-total]
+			last = true
+		}
+		total += len(fresh)
+
+		return handlePage(fresh, pageExcluded) && !last
+	}
+
+	if options.PersonalProjects {
+		if err := s.discoverPersonalProjects(ctx, options, emit); err != nil {
+			return err
+		}
+	} else {
+		listOptions, err := s.resolveListOptions(ctx, options)
+		if err != nil {
+			return err
+		}
+		if _, err := s.listScope(ctx, listOptions, options.Verbose, emit); err != nil {
+			return err
+		}
+	}
+
+	if options.Verbose {
+		fmt.Fprintf(ui.Console, "DEBUG: Total projects found across all pages: %d\n", total)
+		if excluded > 0 {
+			fmt.Fprintf(ui.Console, "DEBUG: Projects excluded by filters: %d\n", excluded)
+		}
+		if duplicates > 0 {
+			fmt.Fprintf(ui.Console, "DEBUG: Projects skipped because an earlier namespace already listed them: %d\n", duplicates)
+		}
+	}
+
+	return nil
+}
+
+// newListOptions returns the options shared by every project listing, including
+// the attribute criteria GitLab can evaluate
+func (s *Scanner) newListOptions() *api.ListProjectsOptions {
+	trueVal := true
+	listOptions := &api.ListProjectsOptions{
+		Page:       1,
+		PerPage:    ProjectsPerPage,
+		Statistics: &trueVal,
+		Archived:   nil, // Get ALL projects unless the attribute filter selects otherwise
+		// Keyset pagination isn't subject to GitLab's 50,000 result offset limit
+		Pagination: "keyset",
+	}
+	s.attributes.apply(listOptions)
+	return listOptions
+}
+
+// listScope pages through a project listing, passing each page to emit. Incremental
+// scans first list only the projects active since the baseline, so workers start on
+// them right away, then list every project of the same scope again without
+// statistics: projects already listed are skipped, and the inactive ones are kept
+// from the baseline. Projects deleted since the baseline, moved out of the scope or
+// now excluded by the filters aren't listed, so they drop out of the report. It
+// returns false when emit asked to stop.
+func (s *Scanner) listScope(ctx context.Context, listOptions *api.ListProjectsOptions, verbose bool, emit func([]*api.Project) bool) (bool, error) {
+	if s.baseline == nil {
+		return s.listProjects(ctx, listOptions, verbose, emit)
+	}
+
+	everything := *listOptions
+	everything.Statistics = nil
+
+	// GitLab versions and endpoints ignoring last_activity_after list every project
+	// here already, and the second listing only finds projects created meanwhile
+	since := s.baseline.Since
+	if listOptions.LastActivityAfter == nil || listOptions.LastActivityAfter.Before(since) {
+		listOptions.LastActivityAfter = &since
+	}
+	more, err := s.listProjects(ctx, listOptions, verbose, emit)
+	if err != nil || !more {
+		return more, err
+	}
+	if verbose {
+		fmt.Fprintf(ui.Console, "DEBUG: Listing every project to keep the inactive ones from the baseline\n")
+	}
+	return s.listProjects(ctx, &everything, verbose, emit)
+}
+
+// resolveListOptions returns the listing options for the namespace or group being
+// scanned. A namespace may be a group or a user's personal namespace.
+func (s *Scanner) resolveListOptions(ctx context.Context, options *models.ScanOptions) (*api.ListProjectsOptions, error) {
+	listOptions := s.newListOptions()
+
+	if options.Namespace != "" {
+		if options.Verbose {
+			fmt.Fprintf(ui.Console, "DEBUG: Resolving namespace '%s'...\n", options.Namespace)
+		}
+		namespace, err := s.client.GetNamespace(ctx, options.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve namespace '%s': %w", options.Namespace, err)
+		}
+
+		if namespace.Kind == api.NamespaceKindUser {
+			listOptions.User = namespace.FullPath
+			// Keyset pagination is only offered for instance and group project lists
+			listOptions.Pagination = ""
+			if options.Verbose {
+				fmt.Fprintf(ui.Console, "DEBUG: Resolved namespace '%s' to the personal projects of user %s\n", options.Namespace, namespace.FullPath)
+			}
+			return listOptions, nil
+		}
+
+		listOptions.GroupID = &namespace.ID
+		if options.Verbose {
+			fmt.Fprintf(ui.Console, "DEBUG: Resolved namespace '%s' to group ID %d\n", options.Namespace, namespace.ID)
+		}
+	} else if options.GroupID != nil {
+		listOptions.GroupID = options.GroupID
+	}
+
+	if listOptions.GroupID != nil && options.Verbose {
+		fmt.Fprintf(ui.Console, "DEBUG: Filtering projects by group ID: %d\n", *listOptions.GroupID)
+	}
+
+	return listOptions, nil
+}
+
+// discoverPersonalProjects lists the personal projects of every user, one user at
+// a time. Only administrators can list the private projects of other users.
+func (s *Scanner) discoverPersonalProjects(ctx context.Context, options *models.ScanOptions, emit func([]*api.Project) bool) error {
+	user, err := s.client.GetCurrentUser(ctx)
+	switch {
+	case err != nil:
+		fmt.Fprintf(ui.Console, "Warning: failed to check whether the token belongs to an administrator: %v. If it does not, only personal projects visible to this token will be found\n", err)
+	case !user.IsAdmin:
+		fmt.Fprintf(ui.Console, "Warning: %s is not an administrator; only personal projects visible to this token will be found\n", user.Username)
+	}
+
+	users := 0
+	for pageNumber := 1; ; pageNumber++ {
+		page, err := s.client.ListUsers(ctx, pageNumber, ProjectsPerPage)
+		if err != nil {
+			return fmt.Errorf("failed to list users (page %d): %w", pageNumber, err)
+		}
+
+		for _, user := range page {
+			listOptions := s.newListOptions()
+			listOptions.User = strconv.Itoa(user.ID)
+			listOptions.Pagination = ""
+
+			more, err := s.listScope(ctx, listOptions, false, emit)
+			var httpErr *api.HTTPError
+			if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+				// The user was deleted since the user list was fetched
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to list personal projects of %s: %w", user.Username, err)
+			}
+			if !more {
+				return nil
+			}
+		}
+		users += len(page)
+
+		if len(page) < ProjectsPerPage {
+			break
+		}
+	}
+
+	if options.Verbose {
+		fmt.Fprintf(ui.Console, "DEBUG: Listed the personal projects of %d users\n", users)
+	}
+
+	return nil
+}
+
+// listProjects pages through a project listing, passing each page to emit. It
+// returns false when emit asked to stop.
+func (s *Scanner) listProjects(ctx context.Context, listOptions *api.ListProjectsOptions, verbose bool, emit func([]*api.Project) bool) (bool, error) {
+	for pageNumber := 1; ; pageNumber++ {
+		page, err := s.client.ListProjects(ctx, listOptions)
+		if err != nil {
+			return false, fmt.Errorf("failed to list projects (page %d): %w", pageNumber, err)
+		}
+		projects := page.Projects
+
+		if verbose {
+			fmt.Fprintf(ui.Console, "DEBUG: Page %d returned %d projects\n", pageNumber, len(projects))
+		}
+
+		if len(projects) == 0 {
+			return true, nil
+		}
+
+		if !emit(projects) {
+			return false, nil
+		}
+
+		if page.Keyset {
+			if page.NextCursor == "" {
+				return true, nil
+			}
+			listOptions.Cursor = page.NextCursor
+			continue
+		}
+
+		// Offset pagination (older GitLab versions): stop after a short page
+		if len(projects) < listOptions.PerPage {
+			return true, nil
+		}
+
+		// Move to next page
+		listOptions.Pagination = ""
+		listOptions.Page = pageNumber + 1
+	}
+}
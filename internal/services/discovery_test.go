@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/api"
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+)
+
+// newOffsetProjectServer starts a fake GitLab without keyset pagination serving
+// count projects. Like GitLab, pages are ordered newest first unless order_by=id
+// is requested. With rejectKeyset, keyset requests fail with 400 Bad Request;
+// otherwise the pagination parameter is ignored.
+func newOffsetProjectServer(t *testing.T, count int, rejectKeyset bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/projects" {
+			http.NotFound(w, r)
+			return
+		}
+		query := r.URL.Query()
+		if rejectKeyset && query.Get("pagination") == "keyset" {
+			http.Error(w, `{"error":"pagination does not have a valid value"}`, http.StatusBadRequest)
+			return
+		}
+
+		page, _ := strconv.Atoi(query.Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		perPage, _ := strconv.Atoi(query.Get("per_page"))
+		start := min((page-1)*perPage, count)
+		end := min(start+perPage, count)
+
+		projects := make([]map[string]interface{}, 0, end-start)
+		for i := start; i < end; i++ {
+			id := count - i
+			if query.Get("order_by") == "id" {
+				id = i + 1
+			}
+			projects = append(projects, map[string]interface{}{"id": id, "path_with_namespace": "group/project-" + strconv.Itoa(id)})
+		}
+		w.Header().Set("X-Page", strconv.Itoa(page))
+		w.Header().Set("X-Next-Page", "")
+		if end < count {
+			w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(projects); err != nil {
+			t.Errorf("failed to write projects: %v", err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDiscoverProjectsWithoutKeyset(t *testing.T) {
+	tests := []struct {
+		name         string
+		count        int
+		rejectKeyset bool
+	}{
+		{"ignored keyset", 250, false},
+		{"rejected keyset", 250, true},
+		{"full last page", 300, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newOffsetProjectServer(t, tt.count, tt.rejectKeyset)
+			client, err := api.NewRestClient(server.URL, "token", nil)
+			if err != nil {
+				t.Fatalf("NewRestClient: %v", err)
+			}
+
+			found := make(map[int]bool)
+			scanner := NewScanner(client)
+			err = scanner.discoverProjects(context.Background(), &models.ScanOptions{}, func(projects []*api.Project, excluded int) bool {
+				for _, project := range projects {
+					found[project.ID] = true
+				}
+				return true
+			})
+			if err != nil {
+				t.Fatalf("discoverProjects: %v", err)
+			}
+
+			if len(found) != tt.count {
+				t.Fatalf("found %d projects, want %d", len(found), tt.count)
+			}
+			for id := 1; id <= tt.count; id++ {
+				if !found[id] {
+					t.Errorf("project %d not found", id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RegexPrefix marks a filter pattern as a regular expression instead of a glob
+const RegexPrefix = "re:"
+
+// ProjectFilter selects projects by matching their full path (e.g.,
+// "platform/tools/legacy-api") against include and exclude patterns. A project is
+// selected when it matches at least one include pattern (or none are given) and no
+// exclude pattern. A nil filter selects every project.
+type ProjectFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// NewProjectFilter compiles include and exclude patterns. Patterns are globs matched
+// against the whole path, case-insensitively: "*" and "?" stay within one path
+// segment, "**" spans any number of segments and "[...]" matches a character class.
+// Patterns starting with "re:" are regular expressions matched anywhere in the path.
+// It returns nil when no patterns are given.
+func NewProjectFilter(include, exclude []string) (*ProjectFilter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+
+	filter := &ProjectFilter{}
+	for _, pattern := range include {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+		filter.include = append(filter.include, re)
+	}
+	for _, pattern := range exclude {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+		filter.exclude = append(filter.exclude, re)
+	}
+	return filter, nil
+}
+
+// Match reports whether the project at path is selected by the filter
+func (f *ProjectFilter) Match(path string) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.include) > 0 && !matchAny(f.include, path) {
+		return false
+	}
+	return !matchAny(f.exclude, path)
+}
+
+// matchAny reports whether any of the patterns matches path
+func matchAny(patterns []*regexp.Regexp, path string) bool {
+	for _, re := range patterns {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// compilePattern compiles a glob, or a regular expression prefixed with "re:"
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, RegexPrefix) {
+		expr := strings.TrimPrefix(pattern, RegexPrefix)
+		if expr == "" {
+			return nil, fmt.Errorf("empty regular expression")
+		}
+		return regexp.Compile(expr)
+	}
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+	return regexp.Compile(globToRegexp(pattern))
+}
+
+// globToRegexp translates a path glob into an anchored, case-insensitive regular expression
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	b.WriteString("(?i)^")
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && strings.HasPrefix(glob[i:], "**/"):
+			// Zero or more leading segments
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("$")
+	return b.String()
+}
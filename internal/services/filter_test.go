@@ -0,0 +1,79 @@
+package services
+
+import "testing"
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		glob string
+		want string
+	}{
+		{"platform/api", `(?i)^platform/api$`},
+		{"platform/*", `(?i)^platform/[^/]*$`},
+		{"platform/**", `(?i)^platform/.*$`},
+		{"**/legacy-*", `(?i)^(?:.*/)?legacy-[^/]*$`},
+		{"platform/**/api", `(?i)^platform/(?:.*/)?api$`},
+		{"api-v?", `(?i)^api-v[^/]$`},
+		{"api-[0-9]", `(?i)^api-[0-9]$`},
+		{"api-[!abc]", `(?i)^api-[^abc]$`},
+		{`api-[\]`, `(?i)^api-[\\]$`},
+		{"api-[", `(?i)^api-\[$`},
+		{"team.name/a+b(1)", `(?i)^team\.name/a\+b\(1\)$`},
+		{"$price^", `(?i)^\$price\^$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob, func(t *testing.T) {
+			if got := globToRegexp(tt.glob); got != tt.want {
+				t.Errorf("globToRegexp(%q) = %q, want %q", tt.glob, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectFilterMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		path    string
+		want    bool
+	}{
+		{"no patterns", nil, nil, "platform/api", true},
+		{"star stays in segment", []string{"platform/*"}, nil, "platform/tools/api", false},
+		{"double star spans segments", []string{"platform/**"}, nil, "platform/tools/api", true},
+		{"leading double star matches no segment", []string{"**/legacy-*"}, nil, "legacy-api", true},
+		{"leading double star matches segments", []string{"**/legacy-*"}, nil, "platform/tools/legacy-api", true},
+		{"negated class", []string{"api-[!abc]"}, nil, "api-a", false},
+		{"negated class match", []string{"api-[!abc]"}, nil, "api-d", true},
+		{"dot is literal", []string{"team.name/*"}, nil, "teamxname/api", false},
+		{"case-insensitive", []string{"Platform/API"}, nil, "platform/api", true},
+		{"excluded", []string{"platform/**"}, []string{"**/legacy-*"}, "platform/legacy-api", false},
+		{"regex anywhere", []string{"re:tools/"}, nil, "platform/tools/api", true},
+		{"regex is case-sensitive", []string{"re:Tools"}, nil, "platform/tools/api", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := NewProjectFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("NewProjectFilter: %v", err)
+			}
+			if got := filter.Match(tt.path); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewProjectFilterInvalid(t *testing.T) {
+	for _, pattern := range []string{"", "re:", "re:(", "api-[]"} {
+		t.Run(pattern, func(t *testing.T) {
+			if _, err := NewProjectFilter([]string{pattern}, nil); err == nil {
+				t.Errorf("include pattern %q was accepted", pattern)
+			}
+			if _, err := NewProjectFilter(nil, []string{pattern}); err == nil {
+				t.Errorf("exclude pattern %q was accepted", pattern)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/api"
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+	"github.com/mona-actions/gh-gitlab-stats/internal/ui"
+)
+
+// ActivityMargin is subtracted from the previous scan time, because GitLab only
+// updates a project's last activity time periodically (at most once an hour)
+const ActivityMargin = time.Hour
+
+// reportTimestamp matches the timestamp in default report file names
+var reportTimestamp = regexp.MustCompile(`\d{4}-\d{2}-\d{2}-\d{2}-\d{2}-\d{2}`)
+
+// Baseline is the report of a previous scan that an incremental scan builds on.
+// Projects without activity since the previous scan keep their previous statistics
+// instead of being rescanned.
+type Baseline struct {
+	Path  string
+	Since time.Time // Projects active after this time are rescanned
+
+	stats        []*models.RepositoryStats
+	byID         map[int]*models.RepositoryStats
+	byURL        map[string]*models.RepositoryStats
+	walksFurther bool // The current page limit walks more pages than the previous scan's
+}
+
+// LoadBaseline reads a previous CSV, JSON or NDJSON report. The previous scan time
+// is the start time recorded in a JSON report's metadata, else the timestamp in a
+// default report file name, else the file's modification time. maxPages is the
+// page limit of the current scan (0 = no limit); reports that don't record theirs
+// are assumed to have used the default.
+func LoadBaseline(path string, maxPages int) (*Baseline, error) {
+	report, err := ui.ReadReport(path)
+	if err != nil {
+		return nil, err
+	}
+	if report.Metadata != nil && report.Metadata.Partial {
+		return nil, fmt.Errorf("%s is a partial report; resume or rerun that scan before using it as a baseline", path)
+	}
+
+	scannedAt, err := reportTime(path, report)
+	if err != nil {
+		return nil, err
+	}
+
+	previousMaxPages := api.DefaultMaxPages
+	if report.Metadata != nil && report.Metadata.MaxPages != nil {
+		previousMaxPages = *report.Metadata.MaxPages
+	}
+
+	baseline := &Baseline{
+		Path:         path,
+		Since:        scannedAt.Add(-ActivityMargin),
+		stats:        report.Stats,
+		byID:         make(map[int]*models.RepositoryStats, len(report.Stats)),
+		byURL:        make(map[string]*models.RepositoryStats, len(report.Stats)),
+		walksFurther: previousMaxPages != 0 && (maxPages == 0 || maxPages > previousMaxPages),
+	}
+	for _, stat := range report.Stats {
+		if stat.ProjectID != 0 {
+			baseline.byID[stat.ProjectID] = stat
+		}
+		if stat.FullURL != "" {
+			baseline.byURL[stat.FullURL] = stat
+		}
+	}
+	return baseline, nil
+}
+
+// reportTime returns when the scan that wrote a report started
+func reportTime(path string, report *ui.Report) (time.Time, error) {
+	if report.Metadata != nil && !report.Metadata.StartedAt.IsZero() {
+		return report.Metadata.StartedAt, nil
+	}
+
+	if match := reportTimestamp.FindString(filepath.Base(path)); match != "" {
+		if t, err := time.ParseInLocation("2006-01-02-15-04-05", match, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read report %s: %w", path, err)
+	}
+	// The file was last written when the scan finished, so activity during that
+	// scan may be missed
+	fmt.Fprintf(ui.Console, "Warning: %s has no scan time; using its modification time (%s)\n",
+		path, info.ModTime().Format(time.RFC3339))
+	return info.ModTime(), nil
+}
+
+// Len returns the number of projects in the previous report
+func (b *Baseline) Len() int {
+	return len(b.stats)
+}
+
+// Unchanged returns the previous statistics of a project that had no activity
+// since the previous scan, refreshed with the project's current identity. Projects
+// whose previous counts failed or are lower bounds are rescanned, and so are those
+// with truncated counts when the current page limit walks more pages.
+func (b *Baseline) Unchanged(project *api.Project) (*models.RepositoryStats, bool) {
+	if b == nil || project.LastActivityAt == nil || project.LastActivityAt.After(b.Since) {
+		return nil, false
+	}
+
+	previous := b.lookup(project.ID, project.WebURL)
+	if previous == nil || len(previous.FailedMetrics) > 0 || len(previous.EstimatedMetrics) > 0 {
+		return nil, false
+	}
+	if len(previous.TruncatedMetrics) > 0 && b.walksFurther {
+		return nil, false
+	}
+
+	stat := *previous
+	stat.ProjectID = project.ID
+	stat.Namespace = extractNamespace(project.PathWithNamespace)
+	stat.RepoName = project.Name
+	stat.FullURL = project.WebURL
+	stat.IsArchive = project.Archived
+	return &stat, true
+}
+
+// lookup finds the previous statistics of a project by ID or URL
+func (b *Baseline) lookup(id int, webURL string) *models.RepositoryStats {
+	if stat, ok := b.byID[id]; ok && id != 0 {
+		return stat
+	}
+	if stat, ok := b.byURL[webURL]; ok && webURL != "" {
+		return stat
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/api"
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+	"github.com/mona-actions/gh-gitlab-stats/internal/ui"
+)
+
+// writeBaseline writes a JSON report of stats started at startedAt, recording
+// maxPages unless it is negative, and returns its path
+func writeBaseline(t *testing.T, stats []*models.RepositoryStats, startedAt time.Time, maxPages int) string {
+	t.Helper()
+	metadata := &models.ReportMetadata{StartedAt: startedAt}
+	if maxPages >= 0 {
+		metadata.MaxPages = &maxPages
+	}
+	formatter := &ui.JSONFormatter{}
+	formatter.SetMetadata(metadata)
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := formatter.WriteToFile(stats, path); err != nil {
+		t.Fatalf("WriteToFile: %v", err)
+	}
+	return path
+}
+
+// baselineStat returns the previous statistics of a project of the group namespace
+func baselineStat(id int, name string) *models.RepositoryStats {
+	return &models.RepositoryStats{
+		ProjectID:   id,
+		Namespace:   "group",
+		RepoName:    name,
+		FullURL:     "https://gitlab.example.com/group/" + name,
+		CommitCount: 10 * id,
+	}
+}
+
+func TestBaselineUnchanged(t *testing.T) {
+	startedAt := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	inactive := startedAt.Add(-48 * time.Hour)
+	active := startedAt.Add(time.Hour)
+
+	failed := baselineStat(2, "failed")
+	failed.FailedMetrics = []string{"Branch_Count"}
+	estimated := baselineStat(3, "estimated")
+	estimated.EstimatedMetrics = []string{"Issue_Comment_Count"}
+	truncated := baselineStat(4, "truncated")
+	truncated.TruncatedMetrics = []string{"MR_Review_Comment_Count"}
+	stats := []*models.RepositoryStats{baselineStat(1, "api"), failed, estimated, truncated}
+
+	tests := []struct {
+		name             string
+		previousMaxPages int // -1 when the report doesn't record it
+		maxPages         int
+		id               int
+		lastActivity     *time.Time
+		want             bool
+	}{
+		{"inactive", 10, 10, 1, &inactive, true},
+		{"active", 10, 10, 1, &active, false},
+		{"unknown activity", 10, 10, 1, nil, false},
+		{"not in baseline", 10, 10, 99, &inactive, false},
+		{"failed metrics", 10, 10, 2, &inactive, false},
+		{"estimated metrics", 10, 10, 3, &inactive, false},
+		{"truncated with same limit", 10, 10, 4, &inactive, true},
+		{"truncated with lower limit", 10, 5, 4, &inactive, true},
+		{"truncated with higher limit", 10, 20, 4, &inactive, false},
+		{"truncated with no limit", 10, 0, 4, &inactive, false},
+		{"truncated with default limit", -1, api.DefaultMaxPages, 4, &inactive, true},
+		{"truncated without recorded limit", -1, 0, 4, &inactive, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline, err := LoadBaseline(writeBaseline(t, stats, startedAt, tt.previousMaxPages), tt.maxPages)
+			if err != nil {
+				t.Fatalf("LoadBaseline: %v", err)
+			}
+
+			project := &api.Project{
+				ID:                tt.id,
+				Name:              "renamed",
+				PathWithNamespace: "other/renamed",
+				WebURL:            "https://gitlab.example.com/other/renamed",
+				Archived:          true,
+				LastActivityAt:    tt.lastActivity,
+			}
+			stat, ok := baseline.Unchanged(project)
+			if ok != tt.want {
+				t.Fatalf("Unchanged = %v, want %v", ok, tt.want)
+			}
+			if !ok {
+				return
+			}
+
+			// The previous statistics are kept under the project's current identity
+			if stat.CommitCount != 10*tt.id {
+				t.Errorf("CommitCount = %d, want %d", stat.CommitCount, 10*tt.id)
+			}
+			if stat.Namespace != "other" || stat.RepoName != "renamed" || stat.FullURL != project.WebURL || !stat.IsArchive {
+				t.Errorf("identity not refreshed: %s/%s %s archived=%v", stat.Namespace, stat.RepoName, stat.FullURL, stat.IsArchive)
+			}
+		})
+	}
+}
+
+func TestIncrementalDiscovery(t *testing.T) {
+	startedAt := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	activity := map[int]time.Time{
+		1: startedAt.Add(-48 * time.Hour), // Inactive, kept from the baseline
+		2: startedAt.Add(time.Hour),       // Active, rescanned
+		4: startedAt.Add(-48 * time.Hour), // Created after the baseline was written
+	}
+	// Project 3 was deleted since the baseline
+	stats := []*models.RepositoryStats{baselineStat(1, "project-1"), baselineStat(2, "project-2"), baselineStat(3, "project-3")}
+	baseline, err := LoadBaseline(writeBaseline(t, stats, startedAt, api.DefaultMaxPages), api.DefaultMaxPages)
+	if err != nil {
+		t.Fatalf("LoadBaseline: %v", err)
+	}
+
+	var listings []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		listings = append(listings, query.Get("last_activity_after"))
+		var after time.Time
+		if value := query.Get("last_activity_after"); value != "" {
+			after, _ = time.Parse(time.RFC3339, value)
+		}
+
+		projects := []map[string]interface{}{}
+		for id := 1; id <= 4; id++ {
+			lastActivity, ok := activity[id]
+			if !ok || lastActivity.Before(after) {
+				continue
+			}
+			projects = append(projects, map[string]interface{}{
+				"id":                  id,
+				"name":                "project-" + strconv.Itoa(id),
+				"path_with_namespace": "group/project-" + strconv.Itoa(id),
+				"web_url":             "https://gitlab.example.com/group/project-" + strconv.Itoa(id),
+				"last_activity_at":    lastActivity.Format(time.RFC3339),
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(projects); err != nil {
+			t.Errorf("failed to write projects: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client, err := api.NewRestClient(server.URL, "token", nil)
+	if err != nil {
+		t.Fatalf("NewRestClient: %v", err)
+	}
+	scanner := NewScanner(client)
+	scanner.SetBaseline(baseline)
+
+	var scanned, kept []int
+	err = scanner.discoverProjects(context.Background(), &models.ScanOptions{}, func(projects []*api.Project, excluded int) bool {
+		pending, unchanged := scanner.restoreFromBaseline(projects)
+		for _, project := range pending {
+			scanned = append(scanned, project.ID)
+		}
+		for _, stat := range unchanged {
+			kept = append(kept, stat.ProjectID)
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatalf("discoverProjects: %v", err)
+	}
+
+	if len(listings) != 2 || listings[0] == "" || listings[1] != "" {
+		t.Errorf("got listings with last_activity_after %q, want the active projects then every project", listings)
+	}
+	if !slices.Equal(scanned, []int{2, 4}) {
+		t.Errorf("scanned projects %v, want [2 4]", scanned)
+	}
+	if !slices.Equal(kept, []int{1}) {
+		t.Errorf("kept projects %v, want [1]", kept)
+	}
+}
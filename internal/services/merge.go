@@ -0,0 +1,116 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+	"github.com/mona-actions/gh-gitlab-stats/internal/ui"
+)
+
+// MergedReport is the consolidation of several reports
+type MergedReport struct {
+	Stats      []*models.RepositoryStats
+	Duplicates int       // Rows dropped because a newer row of the same project was kept
+	OldestScan time.Time // Start of the oldest scan that contributed a report
+	GitLabURL  string    // GitLab instance of the reports, if they all name the same one
+	Partial    bool      // At least one report was written by an interrupted scan
+}
+
+// sourceReport is a report being merged, along with when its scan started
+type sourceReport struct {
+	path      string
+	report    *ui.Report
+	scannedAt time.Time
+}
+
+// MergeReports reads CSV, JSON and NDJSON reports and combines their projects into
+// one list. A project found in several reports, or several times in one report,
+// keeps the row of the newest scan; rows are matched by project ID on the same
+// host, else by URL. The scan time of a report is found like that of a baseline.
+func MergeReports(paths []string) (*MergedReport, error) {
+	sources := make([]sourceReport, 0, len(paths))
+	for _, path := range paths {
+		report, err := ui.ReadReport(path)
+		if err != nil {
+			return nil, err
+		}
+		scannedAt, err := reportTime(path, report)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, sourceReport{path: path, report: report, scannedAt: scannedAt})
+	}
+
+	// Newer rows replace older ones; of two reports from the same time, the later
+	// argument wins
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].scannedAt.Before(sources[j].scannedAt)
+	})
+
+	merged := &MergedReport{}
+	gitlabURLs := make(map[string]bool)
+	byID := make(map[string]int)
+	byURL := make(map[string]int)
+	for i, source := range sources {
+		if i == 0 {
+			merged.OldestScan = source.scannedAt
+		}
+		if metadata := source.report.Metadata; metadata != nil {
+			if metadata.Partial {
+				fmt.Fprintf(ui.Console, "Warning: %s is a partial report; projects its scan didn't reach are missing\n", source.path)
+				merged.Partial = true
+			}
+			if metadata.GitLabURL != "" {
+				gitlabURLs[metadata.GitLabURL] = true
+				merged.GitLabURL = metadata.GitLabURL
+			}
+		}
+
+		for _, stat := range source.report.Stats {
+			idKey := projectIDKey(stat)
+			index, ok := byID[idKey]
+			if !ok || idKey == "" {
+				index, ok = byURL[stat.FullURL]
+				ok = ok && stat.FullURL != ""
+			}
+
+			if ok {
+				merged.Stats[index] = stat
+				merged.Duplicates++
+			} else {
+				index = len(merged.Stats)
+				merged.Stats = append(merged.Stats, stat)
+			}
+			if idKey != "" {
+				byID[idKey] = index
+			}
+			if stat.FullURL != "" {
+				byURL[stat.FullURL] = index
+			}
+		}
+	}
+	if len(gitlabURLs) > 1 {
+		merged.GitLabURL = ""
+	}
+
+	sort.SliceStable(merged.Stats, func(i, j int) bool {
+		return projectPath(merged.Stats[i]) < projectPath(merged.Stats[j])
+	})
+	return merged, nil
+}
+
+// projectIDKey identifies a project by its ID and the host of its URL, since IDs
+// are only unique within one GitLab instance. It is empty when the ID is unknown.
+func projectIDKey(stat *models.RepositoryStats) string {
+	if stat.ProjectID == 0 {
+		return ""
+	}
+	host := ""
+	if parsed, err := url.Parse(stat.FullURL); err == nil {
+		host = parsed.Host
+	}
+	return fmt.Sprintf("%s#%d", host, stat.ProjectID)
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+)
+
+// Ways of keeping projects together when planning waves
+const (
+	PlanByNamespace = "namespace" // Projects of the same namespace
+	PlanByGroup     = "group"     // Projects of the same top-level group or user
+)
+
+// CostModel estimates how long a project takes to migrate from its statistics.
+// The estimate is the base time plus the time for each unit of size and content.
+type CostModel struct {
+	BaseMinutes            float64 `json:"base_minutes"`             // Fixed overhead per project (setup, validation)
+	MinutesPerGB           float64 `json:"minutes_per_gb"`           // Per GB of repository
+	MinutesPerLFSGB        float64 `json:"minutes_per_lfs_gb"`       // Per GB of LFS objects
+	MinutesPer1000Commits  float64 `json:"minutes_per_1000_commits"` // Per 1000 commits
+	MinutesPer1000MRs      float64 `json:"minutes_per_1000_mrs"`     // Per 1000 merge requests
+	MinutesPer1000Issues   float64 `json:"minutes_per_1000_issues"`  // Per 1000 issues
+	MinutesPer1000Comments float64 `json:"minutes_per_1000_comments"`
+}
+
+// DefaultCostModel returns a cost model for typical GitHub Enterprise Importer
+// migrations. Real durations vary with the source instance and network, so tune it
+// with the timings of a pilot wave.
+func DefaultCostModel() *CostModel {
+	return &CostModel{
+		BaseMinutes:            10,
+		MinutesPerGB:           15,
+		MinutesPerLFSGB:        20,
+		MinutesPer1000Commits:  1,
+		MinutesPer1000MRs:      15,
+		MinutesPer1000Issues:   10,
+		MinutesPer1000Comments: 2,
+	}
+}
+
+// LoadCostModel reads a cost model from a JSON file. Settings missing from the
+// file keep their defaults.
+func LoadCostModel(path string) (*CostModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cost model %s: %w", path, err)
+	}
+
+	model := DefaultCostModel()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(model); err != nil {
+		return nil, fmt.Errorf("invalid cost model %s: %w", path, err)
+	}
+	for _, value := range []float64{model.BaseMinutes, model.MinutesPerGB, model.MinutesPerLFSGB, model.MinutesPer1000Commits,
+		model.MinutesPer1000MRs, model.MinutesPer1000Issues, model.MinutesPer1000Comments} {
+		if value < 0 {
+			return nil, fmt.Errorf("invalid cost model %s: times can't be negative", path)
+		}
+	}
+	return model, nil
+}
+
+// EstimateHours returns the estimated migration time of a project in hours
+func (m *CostModel) EstimateHours(stat *models.RepositoryStats) float64 {
+	comments := stat.MRReviewCommentCount + stat.IssueCommentCount
+	minutes := m.BaseMinutes +
+		m.MinutesPerGB*stat.RepoSizeMB/1024 +
+		m.MinutesPerLFSGB*stat.LFSSizeMB/1024 +
+		m.MinutesPer1000Commits*float64(stat.CommitCount)/1000 +
+		m.MinutesPer1000MRs*float64(stat.MRCount)/1000 +
+		m.MinutesPer1000Issues*float64(stat.IssueCount)/1000 +
+		m.MinutesPer1000Comments*float64(comments)/1000
+	return minutes / 60
+}
+
+// planUnit is a set of projects kept in the same wave when possible
+type planUnit struct {
+	key      string
+	projects []*models.PlannedProject
+	hours    float64
+}
+
+// PlanWaves estimates the migration time of every project and packs the projects
+// into waves of at most maxHours, keeping the projects of a namespace (or group)
+// in the same wave. Namespaces are placed largest first into the first wave with
+// room left; a namespace larger than a wave is split across waves, and a project
+// larger than a wave gets a wave of its own. Projects with a blocker are left out;
+// projects of reports written before readiness tiers existed are assessed with the
+// default thresholds first.
+func PlanWaves(stats []*models.RepositoryStats, model *CostModel, maxHours float64, groupBy string) *models.MigrationPlan {
+	plan := &models.MigrationPlan{}
+	readiness := NewReadinessAnalyzer(DefaultReadinessThresholds())
+
+	units := make(map[string]*planUnit)
+	for _, stat := range stats {
+		if stat.MigrationComplexity == "" {
+			readiness.Assess(stat)
+		}
+		project := &models.PlannedProject{Stats: stat, EstimatedHours: model.EstimateHours(stat)}
+		if stat.MigrationComplexity == ComplexityBlocker {
+			project.Note = "blocker: " + strings.Join(stat.MigrationWarnings, "; ")
+			plan.Blocked = append(plan.Blocked, project)
+			continue
+		}
+
+		key := stat.Namespace
+		if groupBy == PlanByGroup {
+			key, _, _ = strings.Cut(stat.Namespace, "/")
+		}
+		if units[key] == nil {
+			units[key] = &planUnit{key: key}
+		}
+		units[key].projects = append(units[key].projects, project)
+		units[key].hours += project.EstimatedHours
+		plan.TotalHours += project.EstimatedHours
+	}
+
+	sorted := make([]*planUnit, 0, len(units))
+	for _, unit := range units {
+		sorted = append(sorted, unit)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].hours != sorted[j].hours {
+			return sorted[i].hours > sorted[j].hours
+		}
+		return sorted[i].key < sorted[j].key
+	})
+
+	for _, unit := range sorted {
+		if unit.hours <= maxHours {
+			addToWave(plan, firstWaveWithRoom(plan, unit.hours, maxHours), unit.projects...)
+			continue
+		}
+
+		// Too large for one wave: place the namespace's projects one at a time
+		sort.SliceStable(unit.projects, func(i, j int) bool {
+			return unit.projects[i].EstimatedHours > unit.projects[j].EstimatedHours
+		})
+		for _, project := range unit.projects {
+			project.Note = fmt.Sprintf("%s split across waves", unit.key)
+			if project.EstimatedHours > maxHours {
+				project.Note = "exceeds the wave limit on its own"
+			}
+			addToWave(plan, firstWaveWithRoom(plan, project.EstimatedHours, maxHours), project)
+		}
+	}
+
+	for _, wave := range plan.Waves {
+		sort.SliceStable(wave.Projects, func(i, j int) bool {
+			return projectPath(wave.Projects[i].Stats) < projectPath(wave.Projects[j].Stats)
+		})
+		namespaces := make(map[string]bool)
+		for _, project := range wave.Projects {
+			namespaces[project.Stats.Namespace] = true
+		}
+		wave.Namespaces = len(namespaces)
+	}
+	return plan
+}
+
+// firstWaveWithRoom returns the first wave that can take hours more work, or nil
+// when a new wave is needed
+func firstWaveWithRoom(plan *models.MigrationPlan, hours, maxHours float64) *models.MigrationWave {
+	for _, wave := range plan.Waves {
+		if wave.Hours+hours <= maxHours {
+			return wave
+		}
+	}
+	return nil
+}
+
+// addToWave adds projects to a wave, starting a new wave when wave is nil
+func addToWave(plan *models.MigrationPlan, wave *models.MigrationWave, projects ...*models.PlannedProject) {
+	if wave == nil {
+		wave = &models.MigrationWave{Number: len(plan.Waves) + 1}
+		plan.Waves = append(plan.Waves, wave)
+	}
+	for _, project := range projects {
+		project.Wave = wave.Number
+		wave.Projects = append(wave.Projects, project)
+		wave.Hours += project.EstimatedHours
+	}
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+)
+
+// Migration complexity tiers, from least to most effort
+const (
+	ComplexityLow     = "low"
+	ComplexityMedium  = "medium"
+	ComplexityHigh    = "high"
+	ComplexityBlocker = "blocker" // Exceeds a hard import limit; needs work before it can be migrated
+)
+
+// complexityRank orders the complexity tiers
+var complexityRank = map[string]int{
+	ComplexityLow:     0,
+	ComplexityMedium:  1,
+	ComplexityHigh:    2,
+	ComplexityBlocker: 3,
+}
+
+// Threshold raises a project's complexity to a tier once a value exceeds the
+// tier's limit. A nil limit doesn't apply.
+type Threshold struct {
+	Medium  *float64 `json:"medium"`
+	High    *float64 `json:"high"`
+	Blocker *float64 `json:"blocker"`
+}
+
+// level returns the highest tier whose limit value exceeds, and that limit
+func (t Threshold) level(value float64) (string, float64) {
+	limits := []struct {
+		tier  string
+		limit *float64
+	}{
+		{ComplexityBlocker, t.Blocker},
+		{ComplexityHigh, t.High},
+		{ComplexityMedium, t.Medium},
+	}
+	for _, l := range limits {
+		if l.limit != nil && value > *l.limit {
+			return l.tier, *l.limit
+		}
+	}
+	return "", 0
+}
+
+// ReadinessThresholds configures the migration readiness checks
+type ReadinessThresholds struct {
+	RepoSizeMB Threshold `json:"repo_size_mb"`
+	LFSSizeMB  Threshold `json:"lfs_size_mb"`
+	MRCount    Threshold `json:"mr_count"`
+	IssueCount Threshold `json:"issue_count"`
+	TagCount   Threshold `json:"tag_count"`
+	Wiki       string    `json:"wiki"` // Tier of projects with a wiki; empty doesn't flag them
+}
+
+// DefaultReadinessThresholds returns thresholds based on GitHub Enterprise Importer
+// limits: repositories over 40 GiB can't be imported, GitHub recommends keeping
+// repositories under 1 GiB, and neither LFS objects nor wikis are part of the
+// migration archive
+func DefaultReadinessThresholds() *ReadinessThresholds {
+	limit := func(value float64) *float64 { return &value }
+	return &ReadinessThresholds{
+		RepoSizeMB: Threshold{Medium: limit(1024), High: limit(10240), Blocker: limit(40960)},
+		LFSSizeMB:  Threshold{Medium: limit(0), High: limit(10240)},
+		MRCount:    Threshold{Medium: limit(5000), High: limit(25000)},
+		IssueCount: Threshold{Medium: limit(5000), High: limit(25000)},
+		TagCount:   Threshold{Medium: limit(1000), High: limit(10000)},
+		Wiki:       ComplexityMedium,
+	}
+}
+
+// LoadReadinessThresholds reads thresholds from a JSON file. Settings missing from
+// the file keep their defaults, and a null limit disables that tier.
+func LoadReadinessThresholds(path string) (*ReadinessThresholds, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read readiness config %s: %w", path, err)
+	}
+
+	thresholds := DefaultReadinessThresholds()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(thresholds); err != nil {
+		return nil, fmt.Errorf("invalid readiness config %s: %w", path, err)
+	}
+	if _, ok := complexityRank[thresholds.Wiki]; !ok && thresholds.Wiki != "" {
+		return nil, fmt.Errorf("invalid readiness config %s: wiki must be \"low\", \"medium\", \"high\", \"blocker\" or \"\"", path)
+	}
+	return thresholds, nil
+}
+
+// readinessCheck compares one statistic of a project to its threshold
+type readinessCheck struct {
+	label     string
+	unit      string // "MB" for sizes, empty for counts
+	hint      string // What the finding means for the migration, if not obvious
+	threshold Threshold
+	value     func(stat *models.RepositoryStats) float64
+}
+
+// ReadinessAnalyzer flags projects that exceed migration thresholds and assigns
+// each project a complexity tier
+type ReadinessAnalyzer struct {
+	checks []readinessCheck
+	wiki   string
+}
+
+// NewReadinessAnalyzer creates an analyzer applying the given thresholds
+func NewReadinessAnalyzer(thresholds *ReadinessThresholds) *ReadinessAnalyzer {
+	return &ReadinessAnalyzer{
+		checks: []readinessCheck{
+			{"Repository size", "MB", "", thresholds.RepoSizeMB,
+				func(stat *models.RepositoryStats) float64 { return stat.RepoSizeMB }},
+			{"LFS size", "MB", "LFS objects are migrated separately", thresholds.LFSSizeMB,
+				func(stat *models.RepositoryStats) float64 { return stat.LFSSizeMB }},
+			{"Merge requests", "", "", thresholds.MRCount,
+				func(stat *models.RepositoryStats) float64 { return float64(stat.MRCount) }},
+			{"Issues", "", "", thresholds.IssueCount,
+				func(stat *models.RepositoryStats) float64 { return float64(stat.IssueCount) }},
+			{"Tags", "", "", thresholds.TagCount,
+				func(stat *models.RepositoryStats) float64 { return float64(stat.TagCount) }},
+		},
+		wiki: thresholds.Wiki,
+	}
+}
+
+// Assess sets the migration complexity and warnings of a project, replacing any
+// earlier assessment. A nil analyzer leaves the project unchanged.
+func (a *ReadinessAnalyzer) Assess(stat *models.RepositoryStats) {
+	if a == nil {
+		return
+	}
+
+	complexity := ComplexityLow
+	var warnings []string
+	raise := func(tier, warning string) {
+		if complexityRank[tier] > complexityRank[complexity] {
+			complexity = tier
+		}
+		warnings = append(warnings, warning)
+	}
+
+	for _, check := range a.checks {
+		value := check.value(stat)
+		tier, limit := check.threshold.level(value)
+		if tier == "" {
+			continue
+		}
+		warning := fmt.Sprintf("%s %s (%s, over %s)", check.label, formatAmount(value, check.unit), tier, formatAmount(limit, check.unit))
+		if check.hint != "" {
+			warning += ": " + check.hint
+		}
+		raise(tier, warning)
+	}
+
+	if stat.HasWiki && a.wiki != "" {
+		raise(a.wiki, fmt.Sprintf("Wiki (%s): wiki pages are migrated separately", a.wiki))
+	}
+
+	stat.MigrationComplexity = complexity
+	stat.MigrationWarnings = warnings
+}
+
+// formatAmount formats a size in megabytes or a count for a warning
+func formatAmount(value float64, unit string) string {
+	if unit == "" {
+		return fmt.Sprintf("%.0f", value)
+	}
+	if value == 0 {
+		return "0 " + unit
+	}
+	if value < 0.1 {
+		return "<0.1 " + unit
+	}
+	if value < 100 {
+		return fmt.Sprintf("%.1f %s", value, unit)
+	}
+	return fmt.Sprintf("%.0f %s", value, unit)
+}
@@ -18,23 +18,85 @@ const (
 	DefaultWorkerCount = 5
 	// ProjectsPerPage is the number of projects to fetch per API request
 	ProjectsPerPage = 100
-	// ProtectedBranchRatio estimates that ~10% of non-default branches are protected
-	ProtectedBranchRatio = 10
 )
 
 // Scanner handles the scanning of GitLab repositories
 type Scanner struct {
-	client api.GitLabClient
+	client     api.GitLabClient
+	checkpoint *Checkpoint
+	stream     ui.StreamFormatter
+	filter     *ProjectFilter
+	attributes *AttributeFilter
+	baseline   *Baseline
+	readiness  *ReadinessAnalyzer
+	listed     map[int]bool // Projects listed by earlier scans of this run
+	stop       chan struct{}
+	stopOnce   sync.Once
 }
 
 // NewScanner creates a new scanner instance
 func NewScanner(client api.GitLabClient) *Scanner {
 	return &Scanner{
 		client: client,
+		listed: make(map[int]bool),
+		stop:   make(chan struct{}),
 	}
 }
 
-// ScanRepositories scans GitLab repositories and collects statistics
+// Interrupt asks the scanner to stop gracefully: discovery is cancelled, no new
+// projects are started, projects already in progress are allowed to finish, and the
+// scan returns the results collected so far. It is safe to call more than once and from any goroutine.
+func (s *Scanner) Interrupt() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// Interrupted reports whether Interrupt has been called
+func (s *Scanner) Interrupted() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetCheckpoint enables checkpointing: completed projects are recorded as they finish,
+// and projects already present in the checkpoint are restored instead of rescanned
+func (s *Scanner) SetCheckpoint(checkpoint *Checkpoint) {
+	s.checkpoint = checkpoint
+}
+
+// SetFilter restricts discovered projects to those selected by filter
+func (s *Scanner) SetFilter(filter *ProjectFilter) {
+	s.filter = filter
+}
+
+// SetAttributeFilter restricts discovered projects to those matching attributes,
+// letting GitLab filter the project lists where it can
+func (s *Scanner) SetAttributeFilter(attributes *AttributeFilter) {
+	s.attributes = attributes
+}
+
+// SetBaseline makes the scan incremental: only projects with activity since the
+// baseline report are rescanned, the others keep their previous statistics
+func (s *Scanner) SetBaseline(baseline *Baseline) {
+	s.baseline = baseline
+}
+
+// SetReadiness assesses the migration readiness of every project in the results
+func (s *Scanner) SetReadiness(readiness *ReadinessAnalyzer) {
+	s.readiness = readiness
+}
+
+// SetStreamFormatter makes the scanner write every completed project to the given
+// formatter as soon as it finishes, instead of only returning it in the result
+func (s *Scanner) SetStreamFormatter(stream ui.StreamFormatter) {
+	s.stream = stream
+}
+
+// ScanRepositories scans GitLab repositories and collects statistics. Projects are
+// handed to the workers as soon as their page has been listed, so scanning starts
+// while discovery is still paging through large instances.
 func (s *Scanner) ScanRepositories(ctx context.Context, options *models.ScanOptions, progress ui.ProgressReporter) (*models.ScanResult, error) {
 	start := time.Now()
 
@@ -43,59 +105,44 @@ func (s *Scanner) ScanRepositories(ctx context.Context, options *models.ScanOpti
 		Errors:          []error{},
 	}
 
-	// Get list of projects
-	fmt.Println("\n🔍 Discovering projects...")
-	projects, err := s.getProjects(ctx, options)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get projects: %w", err)
-	}
-
-	result.TotalProjects = len(projects)
-
-	fmt.Printf("✓ Found %d projects to scan\n", len(projects))
+	numWorkers := workerCount(options)
+	fmt.Fprintln(ui.Console, "\n🔍 Discovering projects...")
 	if options.Verbose {
-		fmt.Printf("  Using %d parallel workers for scanning\n", func() int {
-			numWorkers := DefaultWorkerCount
-			if options.MaxProjects > 0 && options.MaxProjects < numWorkers {
-				numWorkers = options.MaxProjects
-			}
-			return numWorkers
-		}())
+		fmt.Fprintf(ui.Console, "  Using %d parallel workers for scanning\n", numWorkers)
 	}
-	fmt.Println()
-
-	// Initialize progress
-	progress.Start(len(projects))
-
-	// Create channels for worker communication
-	projectChan := make(chan *api.Project, len(projects))
-	resultChan := make(chan *models.RepositoryStats, len(projects))
-	errorChan := make(chan error, len(projects))
+	fmt.Fprintln(ui.Console)
+
+	// Initialize progress; the total grows as discovery advances
+	progress.Start(0)
+
+	// Create channels for discovery and worker communication
+	pages := make(chan []*api.Project)
+	events := make(chan discoveryEvent)
+	projectChan := make(chan *api.Project)
+	resultChan := make(chan *models.RepositoryStats)
+	errorChan := make(chan error)
+
+	// Discover projects, queueing them for the workers page by page. Discovery is
+	// cancelled on interrupt, while workers keep ctx so projects in progress finish.
+	discoveryCtx, stopDiscovery := context.WithCancel(ctx)
+	defer stopDiscovery()
+	go func() {
+		select {
+		case <-s.stop:
+			stopDiscovery()
+		case <-discoveryCtx.Done():
+		}
+	}()
+	go s.discover(discoveryCtx, options, pages, events)
+	go s.feedProjects(ctx, pages, projectChan)
 
 	// Start workers
 	var wg sync.WaitGroup
-	numWorkers := DefaultWorkerCount
-	if options.MaxProjects > 0 && options.MaxProjects < numWorkers {
-		numWorkers = options.MaxProjects
-	}
-
 	for i := 0; i < numWorkers; i++ {
 		wg.Add(1)
 		go s.worker(ctx, projectChan, resultChan, errorChan, &wg, options.Verbose)
 	}
 
-	// Send projects to workers
-	go func() {
-		defer close(projectChan)
-		for _, project := range projects {
-			select {
-			case projectChan <- project:
-			case <-ctx.Done():
-				return
-			}
-		}
-	}()
-
 	// Collect results
 	go func() {
 		wg.Wait()
@@ -103,115 +150,215 @@ func (s *Scanner) ScanRepositories(ctx context.Context, options *models.ScanOpti
 		close(errorChan)
 	}()
 
-	// Process results
-	for {
+	// Process discovery events and results until every channel is closed. Workers stop
+	// picking up new projects on cancellation or interrupt, so everything collected so
+	// far is kept.
+	discovering := true
+	discoveryComplete := false
+	var discovered []*api.Project
+	var discoveryErr error
+	for resultChan != nil || errorChan != nil || events != nil {
 		select {
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if event.done {
+				discovering = false
+				discovered = event.discovered
+				discoveryErr = event.err
+				discoveryComplete = event.complete
+				if event.complete {
+					s.printDiscovered(result)
+				}
+				continue
+			}
+			result.TotalProjects += event.found
+			result.ExcludedProjects += event.excluded
+			for _, stat := range event.restored {
+				s.readiness.Assess(stat)
+				result.RepositoryStats = append(result.RepositoryStats, stat)
+				result.ResumedProjects++
+				result.ProcessedProjects++
+				s.writeStream(stat)
+			}
+			for _, stat := range event.unchanged {
+				s.readiness.Assess(stat)
+				result.RepositoryStats = append(result.RepositoryStats, stat)
+				result.UnchangedProjects++
+				result.ProcessedProjects++
+				s.writeStream(stat)
+			}
+			progress.SetTotal(result.TotalProjects)
+			progress.Update(result.ProcessedProjects)
 		case stat, ok := <-resultChan:
 			if !ok {
-				goto done
+				resultChan = nil
+				continue
 			}
 			if stat != nil {
+				s.readiness.Assess(stat)
 				result.RepositoryStats = append(result.RepositoryStats, stat)
 				result.ProcessedProjects++
-				logProgress(options.Verbose, result.ProcessedProjects, result.TotalProjects, stat)
+				s.recordCheckpoint(stat)
+				s.writeStream(stat)
+				logProgress(options.Verbose, result.ProcessedProjects, result.TotalProjects, discovering, stat)
 				progress.Update(result.ProcessedProjects)
 			}
 		case err, ok := <-errorChan:
 			if !ok {
+				errorChan = nil
 				continue
 			}
 			if err != nil {
 				result.Errors = append(result.Errors, err)
 				if options.Verbose {
-					fmt.Printf("\n❌ Error: %v\n", err)
+					fmt.Fprintf(ui.Console, "\n❌ Error: %v\n", err)
 				}
 			}
-		case <-ctx.Done():
-			progress.Finish()
-			return nil, ctx.Err()
 		}
 	}
 
-done:
 	progress.Finish()
 	result.Duration = time.Since(start)
+
+	stopped := ctx.Err() != nil || s.Interrupted()
+	if discoveryErr != nil && !stopped {
+		if result.TotalProjects == 0 {
+			return nil, fmt.Errorf("failed to get projects: %w", discoveryErr)
+		}
+		// Keep what was scanned, but mark the results partial so the checkpoint is
+		// kept and the scan can be resumed
+		discoveryErr = fmt.Errorf("project discovery stopped after %d projects: %w", result.TotalProjects, discoveryErr)
+		fmt.Fprintf(ui.Console, "\n❌ %v\n", discoveryErr)
+		result.Errors = append(result.Errors, discoveryErr)
+	}
+	if stopped || discoveryErr != nil {
+		result.Interrupted = true
+		result.Unscanned = unscannedProjects(discovered, result.RepositoryStats)
+		if !discoveryComplete {
+			result.Unscanned = append(result.Unscanned, "# project discovery did not finish; undiscovered projects are not listed")
+		}
+	}
 	printScanSummary(result)
 	return result, nil
 }
 
-// getProjects retrieves the list of projects to scan
-func (s *Scanner) getProjects(ctx context.Context, options *models.ScanOptions) ([]*api.Project, error) {
-	// Resolve namespace to group ID if provided
-	var groupID *int
-	if options.Namespace != "" {
-		if options.Verbose {
-			fmt.Printf("DEBUG: Resolving namespace '%s' to group ID...\n", options.Namespace)
-		}
-		group, err := s.client.GetGroupByPath(ctx, options.Namespace)
-		if err != nil {
-			return nil, fmt.Errorf("failed to resolve namespace '%s': %w", options.Namespace, err)
-		}
-		groupID = &group.ID
-		if options.Verbose {
-			fmt.Printf("DEBUG: Resolved namespace '%s' to group ID %d\n", options.Namespace, group.ID)
-		}
-	} else if options.GroupID != nil {
-		groupID = options.GroupID
+// printDiscovered reconciles the progress output once discovery has finished
+func (s *Scanner) printDiscovered(result *models.ScanResult) {
+	if result.ExcludedProjects > 0 {
+		fmt.Fprintf(ui.Console, "\n✓ Discovered %d projects to scan (%d excluded by filters)\n", result.TotalProjects, result.ExcludedProjects)
+	} else {
+		fmt.Fprintf(ui.Console, "\n✓ Discovered %d projects to scan\n", result.TotalProjects)
 	}
+	if result.ResumedProjects > 0 {
+		fmt.Fprintf(ui.Console, "↻ Restored %d completed projects from checkpoint %s (%d remaining)\n",
+			result.ResumedProjects, s.checkpoint.Path(), result.TotalProjects-result.ResumedProjects)
+	}
+	if result.UnchangedProjects > 0 {
+		fmt.Fprintf(ui.Console, "↻ Kept %d projects without activity since %s from %s\n",
+			result.UnchangedProjects, s.baseline.Since.Format(time.RFC3339), s.baseline.Path)
+	}
+}
 
-	trueVal := true
-	listOptions := &api.ListProjectsOptions{
-		Page:       1,
-		PerPage:    ProjectsPerPage,
-		Statistics: &trueVal,
-		Archived:   nil, // Get ALL projects (both archived and non-archived)
+// unscannedProjects returns the paths of projects that have no statistics in the results
+func unscannedProjects(projects []*api.Project, stats []*models.RepositoryStats) []string {
+	scanned := make(map[int]bool, len(stats))
+	for _, stat := range stats {
+		scanned[stat.ProjectID] = true
 	}
 
-	if groupID != nil {
-		listOptions.GroupID = groupID
-		if options.Verbose {
-			fmt.Printf("DEBUG: Filtering projects by group ID: %d\n", *groupID)
+	var unscanned []string
+	for _, project := range projects {
+		if !scanned[project.ID] {
+			unscanned = append(unscanned, project.PathWithNamespace)
 		}
 	}
+	return unscanned
+}
 
-	var allProjects []*api.Project
-
-	for {
-		projects, err := s.client.ListProjects(ctx, listOptions)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list projects (page %d): %w", listOptions.Page, err)
-		}
+// prefetch lets clients that support batching fetch statistics for many projects at once
+func (s *Scanner) prefetch(projects []*api.Project) {
+	prefetcher, ok := s.client.(api.StatisticsPrefetcher)
+	if !ok {
+		return
+	}
+	ids := make([]int, len(projects))
+	for i, project := range projects {
+		ids[i] = project.ID
+	}
+	prefetcher.Prefetch(ids)
+}
 
-		if options.Verbose {
-			fmt.Printf("DEBUG: Page %d returned %d projects\n", listOptions.Page, len(projects))
-		}
+// restoreFromCheckpoint splits projects into those that still need to be scanned
+// and the statistics of those already completed in the checkpoint
+func (s *Scanner) restoreFromCheckpoint(projects []*api.Project) ([]*api.Project, []*models.RepositoryStats) {
+	if s.checkpoint == nil {
+		return projects, nil
+	}
 
-		if len(projects) == 0 {
-			break
+	pending := make([]*api.Project, 0, len(projects))
+	var restored []*models.RepositoryStats
+	for _, project := range projects {
+		if stat, ok := s.checkpoint.Get(project.ID); ok {
+			restored = append(restored, stat)
+			continue
 		}
+		pending = append(pending, project)
+	}
+	return pending, restored
+}
 
-		allProjects = append(allProjects, projects...)
-
-		// Check if we've hit the max projects limit
-		if options.MaxProjects > 0 && len(allProjects) >= options.MaxProjects {
-			allProjects = allProjects[:options.MaxProjects]
-			break
-		}
+// restoreFromBaseline splits projects into those that need to be scanned and the
+// previous statistics of those without activity since the baseline report
+func (s *Scanner) restoreFromBaseline(projects []*api.Project) ([]*api.Project, []*models.RepositoryStats) {
+	if s.baseline == nil {
+		return projects, nil
+	}
 
-		// If we got fewer projects than requested per page, we're done
-		if len(projects) < listOptions.PerPage {
-			break
+	pending := make([]*api.Project, 0, len(projects))
+	var unchanged []*models.RepositoryStats
+	for _, project := range projects {
+		if stat, ok := s.baseline.Unchanged(project); ok {
+			unchanged = append(unchanged, stat)
+			continue
 		}
+		pending = append(pending, project)
+	}
+	return pending, unchanged
+}
 
-		// Move to next page
-		listOptions.Page++
+// recordCheckpoint persists a completed project to the checkpoint, if enabled
+func (s *Scanner) recordCheckpoint(stat *models.RepositoryStats) {
+	if s.checkpoint == nil {
+		return
 	}
+	if err := s.checkpoint.Record(stat); err != nil {
+		fmt.Fprintf(ui.Console, "\nWarning: %v\n", err)
+	}
+}
 
-	if options.Verbose {
-		fmt.Printf("DEBUG: Total projects found across all pages: %d\n", len(allProjects))
+// writeStream writes a completed project to the stream formatter, if enabled
+func (s *Scanner) writeStream(stat *models.RepositoryStats) {
+	if s.stream == nil {
+		return
+	}
+	if err := s.stream.WriteRow(stat); err != nil {
+		fmt.Fprintf(ui.Console, "\nWarning: %v\n", err)
 	}
+}
 
-	return allProjects, nil
+// workerCount returns the number of parallel workers to use for a scan
+func workerCount(options *models.ScanOptions) int {
+	numWorkers := options.Workers
+	if numWorkers <= 0 {
+		numWorkers = DefaultWorkerCount
+	}
+	if options.MaxProjects > 0 && options.MaxProjects < numWorkers {
+		numWorkers = options.MaxProjects
+	}
+	return numWorkers
 }
 
 // worker processes individual projects
@@ -222,6 +369,8 @@ func (s *Scanner) worker(ctx context.Context, projectChan <-chan *api.Project, r
 		select {
 		case <-ctx.Done():
 			return
+		case <-s.stop:
+			return
 		default:
 		}
 
@@ -238,8 +387,8 @@ func (s *Scanner) worker(ctx context.Context, projectChan <-chan *api.Project, r
 // processProject collects comprehensive statistics for a single project
 func (s *Scanner) processProject(ctx context.Context, project *api.Project, verbose bool) (*models.RepositoryStats, error) {
 	if verbose {
-		fmt.Printf("  → Processing: %s (ID: %d)\n", project.PathWithNamespace, project.ID)
-		fmt.Printf("    Fetching detailed statistics...\n")
+		fmt.Fprintf(ui.Console, "  → Processing: %s (ID: %d)\n", project.PathWithNamespace, project.ID)
+		fmt.Fprintf(ui.Console, "    Fetching detailed statistics...\n")
 	}
 
 	// Get detailed statistics
@@ -249,12 +398,24 @@ func (s *Scanner) processProject(ctx context.Context, project *api.Project, verb
 	}
 
 	if verbose {
-		fmt.Printf("    ✓ Retrieved: branches(%d), tags(%d), members(%d), issues(%d), MRs(%d)\n",
+		fmt.Fprintf(ui.Console, "    ✓ Retrieved: branches(%d), tags(%d), members(%d), issues(%d), MRs(%d)\n",
 			stats.BranchCount, stats.TagCount, stats.MemberCount, stats.IssueCount, stats.MergeRequestCount)
-		fmt.Printf("    ✓ Reviews: MR Reviews(%d) | Commits(%d)\n",
+		fmt.Fprintf(ui.Console, "    ✓ Reviews: MR Reviews(%d) | Commits(%d)\n",
 			stats.MergeRequestReviewCount, stats.CommitCount)
-		fmt.Printf("    ✓ Comments: MR(%d), Issue(%d)\n",
+		fmt.Fprintf(ui.Console, "    ✓ Comments: MR(%d), Issue(%d)\n",
 			stats.MergeRequestCommentCount, stats.IssueCommentCount)
+		fmt.Fprintf(ui.Console, "    ✓ MR states: open(%d), merged(%d), closed(%d), draft(%d) | Median merge time(%.1fh)\n",
+			stats.MergeRequestOpenCount, stats.MergeRequestMergedCount, stats.MergeRequestClosedCount,
+			stats.MergeRequestDraftCount, stats.MergeRequestMedianMergeHours)
+		if len(stats.FailedMetrics) > 0 {
+			fmt.Fprintf(ui.Console, "    ⚠ Failed metrics: %s\n", strings.Join(stats.FailedMetrics, ", "))
+		}
+		if len(stats.EstimatedMetrics) > 0 {
+			fmt.Fprintf(ui.Console, "    ⚠ Lower-bound metrics: %s\n", strings.Join(stats.EstimatedMetrics, ", "))
+		}
+		if len(stats.TruncatedMetrics) > 0 {
+			fmt.Fprintf(ui.Console, "    ⚠ Truncated metrics (page limit reached): %s\n", strings.Join(stats.TruncatedMetrics, ", "))
+		}
 	}
 
 	return ConvertToRepoStats(project, stats), nil
@@ -263,6 +424,7 @@ func (s *Scanner) processProject(ctx context.Context, project *api.Project, verb
 // ConvertToRepoStats converts API project and statistics to repository stats model
 func ConvertToRepoStats(project *api.Project, stats *api.ProjectStatistics) *models.RepositoryStats {
 	return &models.RepositoryStats{
+		ProjectID:            project.ID,
 		Namespace:            extractNamespace(project.PathWithNamespace),
 		RepoName:             project.Name,
 		IsEmpty:              project.EmptyRepo,
@@ -272,13 +434,23 @@ func ConvertToRepoStats(project *api.Project, stats *api.ProjectStatistics) *mod
 		IsArchive:            project.Archived,
 		RepoSizeMB:           float64(stats.RepositorySize) / (1024 * 1024),
 		LFSSizeMB:            float64(stats.LFSObjectsSize) / (1024 * 1024),
+		RepoSizeBytes:        stats.RepositorySize,
+		LFSSizeBytes:         stats.LFSObjectsSize,
 		CollaboratorCount:    stats.MemberCount,
-		ProtectedBranchCount: countProtectedBranches(stats.BranchCount),
+		ProtectedBranchCount: stats.ProtectedBranchCount,
 		MRReviewCount:        stats.MergeRequestReviewCount,
 		MilestoneCount:       stats.MilestoneCount,
 		IssueCount:           stats.IssueCount,
 		MRCount:              stats.MergeRequestCount,
 		MRReviewCommentCount: stats.MergeRequestCommentCount,
+		MROpenCount:          stats.MergeRequestOpenCount,
+		MRMergedCount:        stats.MergeRequestMergedCount,
+		MRClosedCount:        stats.MergeRequestClosedCount,
+		MRDraftCount:         stats.MergeRequestDraftCount,
+		MRAvgMergeHours:      stats.MergeRequestAvgMergeHours,
+		MRMedianMergeHours:   stats.MergeRequestMedianMergeHours,
+		MRDiscussionCount:    stats.MergeRequestDiscussionCount,
+		MRDiffNoteCount:      stats.MergeRequestDiffNoteCount,
 		CommitCount:          stats.CommitCount,
 		IssueCommentCount:    stats.IssueCommentCount,
 		ReleaseCount:         stats.ReleaseCount,
@@ -287,6 +459,59 @@ func ConvertToRepoStats(project *api.Project, stats *api.ProjectStatistics) *mod
 		HasWiki:              stats.HasWikiPages,
 		FullURL:              project.WebURL,
 		Created:              project.CreatedAt,
+		FailedMetrics:        stats.FailedMetrics,
+		EstimatedMetrics:     stats.EstimatedMetrics,
+		TruncatedMetrics:     stats.TruncatedMetrics,
+		ProtectedBranches:    convertProtectedBranches(stats.ProtectedBranches),
+	}
+}
+
+// convertProtectedBranches converts GitLab branch protection rules to the report model
+func convertProtectedBranches(protectedBranches []*api.ProtectedBranch) []*models.ProtectedBranchRule {
+	if len(protectedBranches) == 0 {
+		return nil
+	}
+
+	rules := make([]*models.ProtectedBranchRule, 0, len(protectedBranches))
+	for _, branch := range protectedBranches {
+		rules = append(rules, &models.ProtectedBranchRule{
+			Name:                      branch.Name,
+			PushAccess:                describeAccessLevels(branch.PushAccessLevels),
+			MergeAccess:               describeAccessLevels(branch.MergeAccessLevels),
+			AllowForcePush:            branch.AllowForcePush,
+			CodeOwnerApprovalRequired: branch.CodeOwnerApprovalRequired,
+		})
+	}
+	return rules
+}
+
+// describeAccessLevels returns a readable description for each access level,
+// e.g. "Maintainers" or the name of a specific user or group
+func describeAccessLevels(levels []*api.BranchAccessLevel) []string {
+	descriptions := make([]string, 0, len(levels))
+	for _, level := range levels {
+		if level.AccessLevelDescription != "" {
+			descriptions = append(descriptions, level.AccessLevelDescription)
+			continue
+		}
+		descriptions = append(descriptions, accessLevelName(level.AccessLevel))
+	}
+	return descriptions
+}
+
+// accessLevelName maps GitLab's numeric access levels to their display names
+func accessLevelName(level int) string {
+	switch level {
+	case 0:
+		return "No one"
+	case 30:
+		return "Developers + Maintainers"
+	case 40:
+		return "Maintainers"
+	case 60:
+		return "Admins"
+	default:
+		return fmt.Sprintf("Access level %d", level)
 	}
 }
 
@@ -301,49 +526,56 @@ func extractNamespace(pathWithNamespace string) string {
 	return ""
 }
 
-// countProtectedBranches estimates protected branches (GitLab doesn't provide this directly)
-// Assumes main/master branch is protected + ProtectedBranchRatio% of other branches
-func countProtectedBranches(totalBranches int) int {
-	if totalBranches == 0 {
-		return 0
-	}
-	if totalBranches == 1 {
-		return 1
-	}
-	protected := 1 + (totalBranches-1)/ProtectedBranchRatio
-	if protected > totalBranches {
-		return totalBranches
+// logProgress outputs progress information based on verbosity level
+func logProgress(verbose bool, current, total int, discovering bool, stat *models.RepositoryStats) {
+	// While discovery is still running the total is only a lower bound
+	totalLabel := fmt.Sprintf("%d", total)
+	if discovering {
+		totalLabel += "+"
 	}
-	return protected
-}
 
-// logProgress outputs progress information based on verbosity level
-func logProgress(verbose bool, current, total int, stat *models.RepositoryStats) {
 	if verbose {
-		fmt.Printf("\n[%d/%d] ✓ Scanned: %s/%s\n", current, total, stat.Namespace, stat.RepoName)
-		fmt.Printf("    Size: %.0f MB | LFS: %.0f MB | Commits: %d | Issues: %d | MRs: %d | Branches: %d | Tags: %d\n",
+		fmt.Fprintf(ui.Console, "\n[%d/%s] ✓ Scanned: %s/%s\n", current, totalLabel, stat.Namespace, stat.RepoName)
+		fmt.Fprintf(ui.Console, "    Size: %.0f MB | LFS: %.0f MB | Commits: %d | Issues: %d | MRs: %d | Branches: %d | Tags: %d\n",
 			stat.RepoSizeMB, stat.LFSSizeMB, stat.CommitCount, stat.IssueCount, stat.MRCount, stat.BranchCount, stat.TagCount)
 	} else {
-		fmt.Printf("\r[%d/%d] Scanning projects... Current: %s/%s",
-			current, total, utils.Truncate(stat.Namespace, 20), utils.Truncate(stat.RepoName, 30))
+		fmt.Fprintf(ui.Console, "\r[%d/%s] Scanning projects... Current: %s/%s",
+			current, totalLabel, utils.Truncate(stat.Namespace, 20), utils.Truncate(stat.RepoName, 30))
 	}
 }
 
 // printScanSummary prints the final scan summary
 func printScanSummary(result *models.ScanResult) {
 	avgTime := time.Duration(0)
-	if result.ProcessedProjects > 0 {
-		avgTime = result.Duration / time.Duration(result.ProcessedProjects)
-	}
-
-	fmt.Printf("\n\n")
-	fmt.Printf("═══════════════════════════════════════════════════════════════\n")
-	fmt.Printf("                    SCAN COMPLETE\n")
-	fmt.Printf("═══════════════════════════════════════════════════════════════\n")
-	fmt.Printf("  Total projects found:     %d\n", result.TotalProjects)
-	fmt.Printf("  Successfully processed:   %d\n", result.ProcessedProjects)
-	fmt.Printf("  Errors encountered:       %d\n", len(result.Errors))
-	fmt.Printf("  Duration:                 %v\n", result.Duration.Round(time.Second))
-	fmt.Printf("  Average time per project: %v\n", avgTime.Round(time.Millisecond))
-	fmt.Printf("═══════════════════════════════════════════════════════════════\n\n")
+	if scanned := result.ProcessedProjects - result.ResumedProjects; scanned > 0 {
+		avgTime = result.Duration / time.Duration(scanned)
+	}
+
+	title := "                    SCAN COMPLETE"
+	if result.Interrupted {
+		title = "              SCAN INTERRUPTED (PARTIAL RESULTS)"
+	}
+
+	fmt.Fprintf(ui.Console, "\n\n")
+	fmt.Fprintf(ui.Console, "═══════════════════════════════════════════════════════════════\n")
+	fmt.Fprintf(ui.Console, "%s\n", title)
+	fmt.Fprintf(ui.Console, "═══════════════════════════════════════════════════════════════\n")
+	fmt.Fprintf(ui.Console, "  Total projects found:     %d\n", result.TotalProjects)
+	fmt.Fprintf(ui.Console, "  Successfully processed:   %d\n", result.ProcessedProjects)
+	if result.ResumedProjects > 0 {
+		fmt.Fprintf(ui.Console, "  Restored from checkpoint: %d\n", result.ResumedProjects)
+	}
+	if result.UnchangedProjects > 0 {
+		fmt.Fprintf(ui.Console, "  Unchanged since baseline: %d\n", result.UnchangedProjects)
+	}
+	if result.ExcludedProjects > 0 {
+		fmt.Fprintf(ui.Console, "  Excluded by filters:      %d\n", result.ExcludedProjects)
+	}
+	fmt.Fprintf(ui.Console, "  Errors encountered:       %d\n", len(result.Errors))
+	if result.Interrupted {
+		fmt.Fprintf(ui.Console, "  Not scanned:              %d\n", result.UnscannedCount())
+	}
+	fmt.Fprintf(ui.Console, "  Duration:                 %v\n", result.Duration.Round(time.Second))
+	fmt.Fprintf(ui.Console, "  Average time per project: %v\n", avgTime.Round(time.Millisecond))
+	fmt.Fprintf(ui.Console, "═══════════════════════════════════════════════════════════════\n\n")
 }
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+)
+
+// SummarizeStats rolls the statistics of projects up per top-level group and per
+// namespace. Each group row is followed by the rows of its namespaces, and a row
+// totalling every project comes last.
+func SummarizeStats(stats []*models.RepositoryStats) []*models.NamespaceSummary {
+	total := &models.NamespaceSummary{Level: models.SummaryTotal, Namespace: "(all)"}
+	groups := make(map[string]*models.NamespaceSummary)
+	namespaces := make(map[string]*models.NamespaceSummary)
+
+	for _, stat := range stats {
+		group, _, _ := strings.Cut(stat.Namespace, "/")
+		if groups[group] == nil {
+			groups[group] = &models.NamespaceSummary{Level: models.SummaryGroup, Namespace: group}
+		}
+		if namespaces[stat.Namespace] == nil {
+			namespaces[stat.Namespace] = &models.NamespaceSummary{Level: models.SummaryNamespace, Namespace: stat.Namespace}
+		}
+
+		for _, summary := range []*models.NamespaceSummary{total, groups[group], namespaces[stat.Namespace]} {
+			addToSummary(summary, stat)
+		}
+	}
+
+	summaries := make([]*models.NamespaceSummary, 0, len(groups)+len(namespaces)+1)
+	for _, group := range sortedKeys(groups) {
+		summaries = append(summaries, groups[group])
+		for _, namespace := range sortedKeys(namespaces) {
+			if namespace == group || strings.HasPrefix(namespace, group+"/") {
+				summaries = append(summaries, namespaces[namespace])
+			}
+		}
+	}
+	summaries = append(summaries, total)
+
+	for _, summary := range summaries {
+		if summary.ProjectCount > 0 {
+			summary.AvgSizeMB = summary.TotalSizeMB / float64(summary.ProjectCount)
+			summary.AvgLFSSizeMB = summary.TotalLFSSizeMB / float64(summary.ProjectCount)
+		}
+	}
+	return summaries
+}
+
+// addToSummary adds the statistics of one project to a summary
+func addToSummary(summary *models.NamespaceSummary, stat *models.RepositoryStats) {
+	summary.ProjectCount++
+	if stat.IsArchive {
+		summary.ArchivedCount++
+	}
+	if stat.IsEmpty {
+		summary.EmptyCount++
+	}
+	if stat.IsFork {
+		summary.ForkCount++
+	}
+
+	summary.TotalSizeMB += stat.RepoSizeMB
+	summary.MaxSizeMB = max(summary.MaxSizeMB, stat.RepoSizeMB)
+	summary.TotalLFSSizeMB += stat.LFSSizeMB
+	summary.MaxLFSSizeMB = max(summary.MaxLFSSizeMB, stat.LFSSizeMB)
+
+	summary.IssueCount += stat.IssueCount
+	summary.MRCount += stat.MRCount
+	summary.CommitCount += stat.CommitCount
+
+	if activity := lastActivity(stat); activity != nil && (summary.LastActivity == nil || activity.After(*summary.LastActivity)) {
+		summary.LastActivity = activity
+		summary.LastActiveProject = projectPath(stat)
+	}
+}
+
+// lastActivity returns the latest of a project's last push and last update times
+func lastActivity(stat *models.RepositoryStats) *time.Time {
+	latest := stat.LastUpdate
+	if stat.LastPush != nil && (latest == nil || stat.LastPush.After(*latest)) {
+		latest = stat.LastPush
+	}
+	return latest
+}
+
+// sortedKeys returns the keys of a summary map in order
+func sortedKeys(summaries map[string]*models.NamespaceSummary) []string {
+	keys := make([]string, 0, len(summaries))
+	for key := range summaries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"io"
+	"os"
+)
+
+// Console is where progress and status messages are written. It defaults to
+// stdout and is redirected to stderr when the report itself is written to stdout.
+var Console io.Writer = os.Stdout
+
+// nopCloser wraps a writer that must not be closed, such as stdout
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// CreateOutput opens the destination for a report: "-" means stdout,
+// anything else is a file path that is created or truncated
+func CreateOutput(filename string) (io.WriteCloser, error) {
+	if filename == "-" {
+		return nopCloser{os.Stdout}, nil
+	}
+	return os.Create(filename)
+}
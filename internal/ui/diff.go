@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+)
+
+// DiffTopGrowth is the number of fastest growing projects listed in the diff summary
+const DiffTopGrowth = 10
+
+// WriteDiffCSV writes one row per added, removed, renamed or changed project.
+// "-" writes to stdout.
+func WriteDiffCSV(diff *models.ReportDiff, filename string) error {
+	file, err := CreateOutput(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", filename, err)
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(getDiffCSVHeaders()); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, change := range diff.Changes {
+		if err := writer.Write(convertToDiffCSVRow(change)); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to flush CSV output: %w", err)
+	}
+	return file.Close()
+}
+
+// getDiffCSVHeaders returns the diff CSV header row
+func getDiffCSVHeaders() []string {
+	return []string{
+		"Change",
+		"Namespace",
+		"Project",
+		"Previous_Namespace",
+		"Previous_Project",
+		"Full_URL",
+		"Project_Size(mb)",
+		"Size_Change(mb)",
+		"LFS_Size(mb)",
+		"LFS_Change(mb)",
+		"Commit_Change",
+		"Branch_Count",
+		"Branch_Change",
+		"Tag_Count",
+		"Tag_Change",
+		"Archive_Change",
+	}
+}
+
+// convertToDiffCSVRow converts a ProjectChange to a diff CSV row. Removed projects
+// are listed under their last path with current sizes and counts of zero.
+func convertToDiffCSVRow(change *models.ProjectChange) []string {
+	current := change.New
+	previous := change.Old
+	if current == nil {
+		current = &models.RepositoryStats{
+			Namespace: change.Old.Namespace,
+			RepoName:  change.Old.RepoName,
+			FullURL:   change.Old.FullURL,
+		}
+	}
+
+	previousNamespace, previousProject := "", ""
+	if change.Change == models.ChangeRenamed {
+		previousNamespace, previousProject = previous.Namespace, previous.RepoName
+	}
+
+	archiveChange := ""
+	if change.Archived {
+		archiveChange = "archived"
+	} else if change.Unarchived {
+		archiveChange = "unarchived"
+	}
+
+	return []string{
+		change.Change,
+		current.Namespace,
+		current.RepoName,
+		previousNamespace,
+		previousProject,
+		current.FullURL,
+		fmt.Sprintf("%.1f", current.RepoSizeMB),
+		signedMB(change.SizeDeltaMB),
+		fmt.Sprintf("%.1f", current.LFSSizeMB),
+		signedMB(change.LFSDeltaMB),
+		fmt.Sprintf("%+d", change.CommitDelta),
+		fmt.Sprintf("%d", current.BranchCount),
+		fmt.Sprintf("%+d", change.BranchDelta),
+		fmt.Sprintf("%d", current.TagCount),
+		fmt.Sprintf("%+d", change.TagDelta),
+		archiveChange,
+	}
+}
+
+// PrintDiffSummary writes a human-readable summary of a diff
+func PrintDiffSummary(w io.Writer, diff *models.ReportDiff, oldName, newName string) {
+	counts := make(map[string]int)
+	archived, unarchived := 0, 0
+	var sizeDelta, lfsDelta float64
+	branchDelta, tagDelta := 0, 0
+	for _, change := range diff.Changes {
+		counts[change.Change]++
+		if change.Archived {
+			archived++
+		}
+		if change.Unarchived {
+			unarchived++
+		}
+		sizeDelta += change.SizeDeltaMB
+		lfsDelta += change.LFSDeltaMB
+		branchDelta += change.BranchDelta
+		tagDelta += change.TagDelta
+	}
+
+	fmt.Fprintf(w, "\n═══════════════════════════════════════════════════════════════\n")
+	fmt.Fprintf(w, "                    SCAN DIFF\n")
+	fmt.Fprintf(w, "═══════════════════════════════════════════════════════════════\n")
+	fmt.Fprintf(w, "  Old report:      %s (%d projects)\n", oldName, diff.OldProjects)
+	fmt.Fprintf(w, "  New report:      %s (%d projects)\n", newName, diff.NewProjects)
+	fmt.Fprintf(w, "  Added:           %d\n", counts[models.ChangeAdded])
+	fmt.Fprintf(w, "  Removed:         %d\n", counts[models.ChangeRemoved])
+	fmt.Fprintf(w, "  Renamed:         %d\n", counts[models.ChangeRenamed])
+	fmt.Fprintf(w, "  Changed:         %d\n", counts[models.ChangeUpdated])
+	fmt.Fprintf(w, "  Unchanged:       %d\n", diff.Unchanged)
+	fmt.Fprintf(w, "  Newly archived:  %d\n", archived)
+	if unarchived > 0 {
+		fmt.Fprintf(w, "  Unarchived:      %d\n", unarchived)
+	}
+	fmt.Fprintf(w, "  Size change:     %s MB (LFS %s MB)\n", signedMB(sizeDelta), signedMB(lfsDelta))
+	fmt.Fprintf(w, "  Branch change:   %+d\n", branchDelta)
+	fmt.Fprintf(w, "  Tag change:      %+d\n", tagDelta)
+
+	growth := make([]*models.ProjectChange, 0, len(diff.Changes))
+	for _, change := range diff.Changes {
+		if change.New != nil && change.SizeDeltaMB > 0 {
+			growth = append(growth, change)
+		}
+	}
+	sort.SliceStable(growth, func(i, j int) bool {
+		return growth[i].SizeDeltaMB > growth[j].SizeDeltaMB
+	})
+	if len(growth) > DiffTopGrowth {
+		growth = growth[:DiffTopGrowth]
+	}
+	if len(growth) > 0 {
+		fmt.Fprintf(w, "\n  Largest growth:\n")
+		for _, change := range growth {
+			fmt.Fprintf(w, "    %10s MB  %s/%s (%s)\n", signedMB(change.SizeDeltaMB), change.New.Namespace, change.New.RepoName, change.Change)
+		}
+	}
+	fmt.Fprintf(w, "═══════════════════════════════════════════════════════════════\n\n")
+}
+
+// signedMB formats a size change in megabytes with a sign, without showing
+// changes that round to zero as "-0.0"
+func signedMB(delta float64) string {
+	rounded := math.Round(delta*10) / 10
+	if rounded == 0 {
+		rounded = 0
+	}
+	return fmt.Sprintf("%+.1f", rounded)
+}
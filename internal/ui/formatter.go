@@ -3,7 +3,8 @@ package ui
 import (
 	"encoding/csv"
 	"fmt"
-	"os"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/mona-actions/gh-gitlab-stats/internal/models"
@@ -19,43 +20,121 @@ func NewFormatter(format string) (Formatter, error) {
 	switch format {
 	case "csv":
 		return &CSVFormatter{}, nil
+	case "json":
+		return &JSONFormatter{}, nil
+	case "ndjson":
+		return &NDJSONFormatter{}, nil
 	default:
-		return nil, fmt.Errorf("unsupported format: %s (only 'csv' is supported)", format)
+		return nil, fmt.Errorf("unsupported format: %s (supported: 'csv', 'json', 'ndjson')", format)
 	}
 }
 
+// StreamFormatter writes repository statistics incrementally, one project at a
+// time, so completed rows reach disk while a scan is still running
+type StreamFormatter interface {
+	Open(filename string) error
+	WriteRow(stat *models.RepositoryStats) error
+	Close() error
+}
+
+// MetadataSetter is implemented by formatters that include scan metadata in the report.
+// SetMetadata must be called before Close.
+type MetadataSetter interface {
+	SetMetadata(metadata *models.ReportMetadata)
+}
+
+// NewStreamFormatter creates a streaming formatter based on the format type
+func NewStreamFormatter(format string) (StreamFormatter, error) {
+	switch format {
+	case "csv":
+		return &CSVFormatter{}, nil
+	case "json":
+		return &JSONFormatter{}, nil
+	case "ndjson":
+		return &NDJSONFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported streaming format: %s (supported: 'csv', 'json', 'ndjson')", format)
+	}
+}
+
+// writeAll writes a complete set of statistics through a streaming formatter
+func writeAll(f StreamFormatter, stats []*models.RepositoryStats, filename string) error {
+	if err := f.Open(filename); err != nil {
+		return err
+	}
+	for _, stat := range stats {
+		if err := f.WriteRow(stat); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
 // CSVFormatter formats output as CSV
-type CSVFormatter struct{}
+type CSVFormatter struct {
+	file   io.WriteCloser
+	writer *csv.Writer
+}
 
 // WriteToFile writes repository statistics to a CSV file
 func (f *CSVFormatter) WriteToFile(stats []*models.RepositoryStats, filename string) error {
-	file, err := os.Create(filename)
+	return writeAll(f, stats, filename)
+}
+
+// Open creates the CSV file and writes the header row
+func (f *CSVFormatter) Open(filename string) error {
+	file, err := CreateOutput(filename)
 	if err != nil {
 		return fmt.Errorf("failed to create file %s: %w", filename, err)
 	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	if len(stats) == 0 {
-		return nil
-	}
+	f.file = file
+	f.writer = csv.NewWriter(file)
 
-	// Write header
+	// Write header; the file is closed again if that fails
 	header := getCSVHeaders()
-	if err := writer.Write(header); err != nil {
+	if err := f.writer.Write(header); err != nil {
+		f.file.Close()
+		f.file = nil
 		return fmt.Errorf("failed to write header: %w", err)
 	}
+	if err := f.flush(); err != nil {
+		f.file.Close()
+		f.file = nil
+		return err
+	}
+	return nil
+}
 
-	// Write data rows
-	for _, stat := range stats {
-		row := convertToCSVRow(stat)
-		if err := writer.Write(row); err != nil {
-			return fmt.Errorf("failed to write row: %w", err)
-		}
+// WriteRow writes a single project and flushes it to disk immediately
+func (f *CSVFormatter) WriteRow(stat *models.RepositoryStats) error {
+	row := convertToCSVRow(stat)
+	if err := f.writer.Write(row); err != nil {
+		return fmt.Errorf("failed to write row: %w", err)
 	}
+	return f.flush()
+}
+
+// Close flushes any buffered data and closes the CSV file. Calling Close more than once is a no-op.
+func (f *CSVFormatter) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	flushErr := f.flush()
+	closeErr := f.file.Close()
+	f.file = nil
+	if flushErr != nil {
+		return flushErr
+	}
+	return closeErr
+}
 
+// flush pushes buffered rows to the file
+func (f *CSVFormatter) flush() error {
+	f.writer.Flush()
+	if err := f.writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush CSV output: %w", err)
+	}
 	return nil
 }
 
@@ -86,6 +165,21 @@ func getCSVHeaders() []string {
 		"Created",
 		"Last_Push",
 		"Last_Update",
+		"Failed_Metrics",
+		"Protected_Branches",
+		"Estimated_Metrics",
+		"Truncated_Metrics",
+		"MR_Open_Count",
+		"MR_Merged_Count",
+		"MR_Closed_Count",
+		"MR_Draft_Count",
+		"MR_Avg_Merge_Hours",
+		"MR_Median_Merge_Hours",
+		"MR_Discussion_Count",
+		"MR_Diff_Note_Count",
+		"Migration_Complexity",
+		"Migration_Warnings",
+		"Project_ID",
 	}
 }
 
@@ -111,16 +205,40 @@ func convertToCSVRow(stat *models.RepositoryStats) []string {
 		fmt.Sprintf("%d", stat.ReleaseCount),
 		fmt.Sprintf("%d", stat.BranchCount),
 		fmt.Sprintf("%d", stat.TagCount),
-		boolToString(stat.HasWiki),    // Has_Wiki
-		stat.FullURL,                  // Full_URL
-		timeToString(stat.Created),    // Created
-		timeToString(stat.LastPush),   // Last_Push
-		timeToString(stat.LastUpdate), // Last_Update
+		boolToString(stat.HasWiki),                      // Has_Wiki
+		stat.FullURL,                                    // Full_URL
+		timeToString(stat.Created),                      // Created
+		timeToString(stat.LastPush),                     // Last_Push
+		timeToString(stat.LastUpdate),                   // Last_Update
+		strings.Join(stat.FailedMetrics, ";"),           // Failed_Metrics
+		formatProtectedBranches(stat.ProtectedBranches), // Protected_Branches
+		strings.Join(stat.EstimatedMetrics, ";"),        // Estimated_Metrics
+		strings.Join(stat.TruncatedMetrics, ";"),        // Truncated_Metrics
+		fmt.Sprintf("%d", stat.MROpenCount),
+		fmt.Sprintf("%d", stat.MRMergedCount),
+		fmt.Sprintf("%d", stat.MRClosedCount),
+		fmt.Sprintf("%d", stat.MRDraftCount),
+		fmt.Sprintf("%.1f", stat.MRAvgMergeHours),    // MR_Avg_Merge_Hours - one decimal
+		fmt.Sprintf("%.1f", stat.MRMedianMergeHours), // MR_Median_Merge_Hours - one decimal
+		fmt.Sprintf("%d", stat.MRDiscussionCount),
+		fmt.Sprintf("%d", stat.MRDiffNoteCount),
+		stat.MigrationComplexity,                  // Migration_Complexity
+		strings.Join(stat.MigrationWarnings, ";"), // Migration_Warnings
+		projectIDToString(stat.ProjectID),         // Project_ID
 	}
 }
 
 // Helper functions
 
+// projectIDToString leaves the Project_ID cell empty for rows of reports that
+// carried no project IDs
+func projectIDToString(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", id)
+}
+
 func boolToString(b bool) string {
 	if b {
 		return "true"
@@ -128,6 +246,51 @@ func boolToString(b bool) string {
 	return "false"
 }
 
+// protectedBranchSeparators are the characters that structure a Protected_Branches
+// cell; they are escaped with a backslash in branch names and access levels
+const protectedBranchSeparators = `\|;,()`
+
+// formatProtectedBranches renders branch protection rules in a single CSV cell, e.g.
+// "main(push:Maintainers;merge:Developers + Maintainers)|release/*(push:No one;merge:Maintainers;force-push)"
+func formatProtectedBranches(rules []*models.ProtectedBranchRule) string {
+	formatted := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		settings := []string{
+			"push:" + joinEscaped(rule.PushAccess, ","),
+			"merge:" + joinEscaped(rule.MergeAccess, ","),
+		}
+		if rule.AllowForcePush {
+			settings = append(settings, "force-push")
+		}
+		if rule.CodeOwnerApprovalRequired {
+			settings = append(settings, "code-owner-approval")
+		}
+		formatted = append(formatted, fmt.Sprintf("%s(%s)", escapeProtectedBranch(rule.Name), strings.Join(settings, ";")))
+	}
+	return strings.Join(formatted, "|")
+}
+
+// escapeProtectedBranch escapes the separators of a Protected_Branches cell in value
+func escapeProtectedBranch(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(protectedBranchSeparators, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// joinEscaped escapes every value and joins them with sep
+func joinEscaped(values []string, sep string) string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = escapeProtectedBranch(value)
+	}
+	return strings.Join(escaped, sep)
+}
+
 func timeToString(t *time.Time) string {
 	if t == nil {
 		return ""
@@ -138,6 +301,7 @@ func timeToString(t *time.Time) string {
 // ProgressReporter interface for progress reporting
 type ProgressReporter interface {
 	Start(total int)
+	SetTotal(total int)
 	Update(current int)
 	Finish()
 }
@@ -153,6 +317,9 @@ func NewQuietProgress() *QuietProgress {
 // Start is a no-op for quiet progress
 func (p *QuietProgress) Start(total int) {}
 
+// SetTotal is a no-op for quiet progress
+func (p *QuietProgress) SetTotal(total int) {}
+
 // Update is a no-op for quiet progress
 func (p *QuietProgress) Update(current int) {}
 
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"os"
+	"testing"
+)
+
+func TestOpenClosesFileOnWriteFailure(t *testing.T) {
+	// Every write to /dev/full fails with "no space left on device"
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("/dev/full is not available")
+	}
+
+	csvFormatter := &CSVFormatter{}
+	if err := csvFormatter.Open("/dev/full"); err == nil {
+		t.Error("CSV Open succeeded, want an error")
+	}
+	if csvFormatter.file != nil {
+		t.Error("CSV file left open")
+	}
+
+	jsonFormatter := &JSONFormatter{}
+	if err := jsonFormatter.Open("/dev/full"); err == nil {
+		t.Error("JSON Open succeeded, want an error")
+	}
+	if jsonFormatter.file != nil {
+		t.Error("JSON file left open")
+	}
+}
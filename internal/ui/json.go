@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+)
+
+// JSONFormatter formats output as a single JSON document containing the projects
+// and the scan metadata. Projects are streamed into the "projects" array as they
+// complete; the metadata is written when the formatter is closed.
+type JSONFormatter struct {
+	file     io.WriteCloser
+	rows     int
+	metadata *models.ReportMetadata
+}
+
+// WriteToFile writes repository statistics to a JSON file
+func (f *JSONFormatter) WriteToFile(stats []*models.RepositoryStats, filename string) error {
+	return writeAll(f, stats, filename)
+}
+
+// SetMetadata sets the scan metadata written when the document is closed
+func (f *JSONFormatter) SetMetadata(metadata *models.ReportMetadata) {
+	f.metadata = metadata
+}
+
+// Open creates the JSON file and starts the projects array
+func (f *JSONFormatter) Open(filename string) error {
+	file, err := CreateOutput(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", filename, err)
+	}
+	f.file = file
+	f.rows = 0
+
+	if _, err := io.WriteString(f.file, "{\n  \"projects\": ["); err != nil {
+		f.file.Close()
+		f.file = nil
+		return fmt.Errorf("failed to write JSON output: %w", err)
+	}
+	return nil
+}
+
+// WriteRow appends a single project to the projects array
+func (f *JSONFormatter) WriteRow(stat *models.RepositoryStats) error {
+	data, err := marshalJSON(stat, "    ")
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n    "
+	if f.rows == 0 {
+		separator = "\n    "
+	}
+	if _, err := io.WriteString(f.file, separator+string(data)); err != nil {
+		return fmt.Errorf("failed to write JSON output: %w", err)
+	}
+	f.rows++
+	return nil
+}
+
+// Close ends the projects array, writes the metadata and closes the file.
+// Calling Close more than once is a no-op.
+func (f *JSONFormatter) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	defer func() { f.file = nil }()
+
+	metadata := f.metadata
+	if metadata == nil {
+		metadata = &models.ReportMetadata{}
+	}
+	data, err := marshalJSON(metadata, "  ")
+	if err != nil {
+		f.file.Close()
+		return err
+	}
+
+	arrayEnd := "\n  ]"
+	if f.rows == 0 {
+		arrayEnd = "]"
+	}
+	closing := arrayEnd + ",\n  \"metadata\": " + string(data) + "\n}\n"
+	if _, err := io.WriteString(f.file, closing); err != nil {
+		f.file.Close()
+		return fmt.Errorf("failed to write JSON output: %w", err)
+	}
+	return f.file.Close()
+}
+
+// NDJSONFormatter formats output as newline-delimited JSON, one project per line
+type NDJSONFormatter struct {
+	file io.WriteCloser
+}
+
+// WriteToFile writes repository statistics to an NDJSON file
+func (f *NDJSONFormatter) WriteToFile(stats []*models.RepositoryStats, filename string) error {
+	return writeAll(f, stats, filename)
+}
+
+// Open creates the NDJSON file
+func (f *NDJSONFormatter) Open(filename string) error {
+	file, err := CreateOutput(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", filename, err)
+	}
+	f.file = file
+	return nil
+}
+
+// WriteRow writes a single project as one line of JSON
+func (f *NDJSONFormatter) WriteRow(stat *models.RepositoryStats) error {
+	data, err := marshalJSON(stat, "")
+	if err != nil {
+		return err
+	}
+	if _, err := f.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write NDJSON output: %w", err)
+	}
+	return nil
+}
+
+// Close closes the NDJSON file. Calling Close more than once is a no-op.
+func (f *NDJSONFormatter) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// marshalJSON encodes v without HTML escaping (so URLs stay readable), indenting
+// nested lines with the given prefix when it is not empty
+func marshalJSON(v interface{}, prefix string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if prefix != "" {
+		encoder.SetIndent(prefix, "  ")
+	}
+	if err := encoder.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+)
+
+// WritePlanCSV writes one row per project, ordered by wave, followed by the
+// projects left out of the plan. "-" writes to stdout.
+func WritePlanCSV(plan *models.MigrationPlan, filename string) error {
+	file, err := CreateOutput(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", filename, err)
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(getPlanCSVHeaders()); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, wave := range plan.Waves {
+		for _, project := range wave.Projects {
+			if err := writer.Write(convertToPlanCSVRow(project, wave)); err != nil {
+				file.Close()
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+	}
+	for _, project := range plan.Blocked {
+		if err := writer.Write(convertToPlanCSVRow(project, nil)); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to flush CSV output: %w", err)
+	}
+	return file.Close()
+}
+
+// getPlanCSVHeaders returns the wave plan CSV header row
+func getPlanCSVHeaders() []string {
+	return []string{
+		"Wave",
+		"Wave_Hours",
+		"Namespace",
+		"Project",
+		"Full_URL",
+		"Project_Size(mb)",
+		"LFS_Size(mb)",
+		"Commit_Count",
+		"MR_Count",
+		"Issue_Count",
+		"Comment_Count",
+		"Migration_Complexity",
+		"Estimated_Hours",
+		"Note",
+	}
+}
+
+// convertToPlanCSVRow converts a planned project to a wave plan CSV row. Projects
+// left out of the plan have no wave and are listed as blocked.
+func convertToPlanCSVRow(project *models.PlannedProject, wave *models.MigrationWave) []string {
+	waveNumber, waveHours := "blocked", ""
+	if wave != nil {
+		waveNumber, waveHours = fmt.Sprintf("%d", wave.Number), fmt.Sprintf("%.1f", wave.Hours)
+	}
+
+	stat := project.Stats
+	return []string{
+		waveNumber,
+		waveHours,
+		stat.Namespace,
+		stat.RepoName,
+		stat.FullURL,
+		fmt.Sprintf("%.0f", stat.RepoSizeMB),
+		fmt.Sprintf("%.0f", stat.LFSSizeMB),
+		fmt.Sprintf("%d", stat.CommitCount),
+		fmt.Sprintf("%d", stat.MRCount),
+		fmt.Sprintf("%d", stat.IssueCount),
+		fmt.Sprintf("%d", stat.MRReviewCommentCount+stat.IssueCommentCount),
+		stat.MigrationComplexity,
+		fmt.Sprintf("%.2f", project.EstimatedHours),
+		project.Note,
+	}
+}
+
+// PrintPlanSummary writes a human-readable overview of a wave plan
+func PrintPlanSummary(w io.Writer, plan *models.MigrationPlan, maxHours float64) {
+	planned := 0
+	for _, wave := range plan.Waves {
+		planned += len(wave.Projects)
+	}
+
+	fmt.Fprintf(w, "\n═══════════════════════════════════════════════════════════════\n")
+	fmt.Fprintf(w, "                    MIGRATION PLAN\n")
+	fmt.Fprintf(w, "═══════════════════════════════════════════════════════════════\n")
+	fmt.Fprintf(w, "  Planned projects: %d\n", planned)
+	fmt.Fprintf(w, "  Estimated time:   %.1f hours\n", plan.TotalHours)
+	fmt.Fprintf(w, "  Waves:            %d (budget %.1f hours each)\n", len(plan.Waves), maxHours)
+	if over := overBudgetWaves(plan, maxHours); over > 0 {
+		fmt.Fprintf(w, "  Over budget:      %d (single projects larger than the budget)\n", over)
+	}
+	if len(plan.Blocked) > 0 {
+		fmt.Fprintf(w, "  Blocked:          %d (resolve their blockers before planning them)\n", len(plan.Blocked))
+	}
+
+	if len(plan.Waves) > 0 {
+		fmt.Fprintf(w, "\n  %-6s %10s %10s %12s\n", "Wave", "Projects", "Namespaces", "Hours")
+		for _, wave := range plan.Waves {
+			marker := ""
+			if wave.Hours > maxHours {
+				marker = " (over budget)"
+			}
+			fmt.Fprintf(w, "  %-6d %10d %10d %12.1f%s\n", wave.Number, len(wave.Projects), wave.Namespaces, wave.Hours, marker)
+		}
+	}
+	fmt.Fprintf(w, "═══════════════════════════════════════════════════════════════\n\n")
+}
+
+// overBudgetWaves returns the number of waves over the hours budget, which only
+// happens to waves holding a single project larger than the budget
+func overBudgetWaves(plan *models.MigrationPlan, maxHours float64) int {
+	over := 0
+	for _, wave := range plan.Waves {
+		if wave.Hours > maxHours {
+			over++
+		}
+	}
+	return over
+}
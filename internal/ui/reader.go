@@ -0,0 +1,377 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+)
+
+// Report is a report written by an earlier scan
+type Report struct {
+	Format   string // "csv", "json" or "ndjson"
+	Stats    []*models.RepositoryStats
+	Metadata *models.ReportMetadata // Only JSON reports carry metadata
+}
+
+// ReadReport reads a CSV, JSON or NDJSON report. The format is taken from the file
+// extension (.csv, .json, .ndjson or .jsonl), or detected from the content for
+// other names. CSV reports don't include exact sizes in bytes, and those written
+// by older versions have no project IDs, so those fields are left at zero.
+func ReadReport(filename string) (*Report, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report %s: %w", filename, err)
+	}
+
+	format := reportFormat(filename, data)
+	var report *Report
+	switch format {
+	case "json":
+		report, err = readJSONReport(data)
+	case "ndjson":
+		report, err = readNDJSONReport(data)
+	default:
+		report, err = readCSVReport(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report %s: %w", filename, err)
+	}
+	report.Format = format
+	return report, nil
+}
+
+// reportFormat returns the format of a report from its extension or content
+func reportFormat(filename string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return "csv"
+	case ".json":
+		return "json"
+	case ".ndjson", ".jsonl":
+		return "ndjson"
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if !bytes.HasPrefix(trimmed, []byte("{")) {
+		return "csv"
+	}
+	// A JSON report is one document; NDJSON has one object per line
+	var document struct {
+		Projects json.RawMessage `json:"projects"`
+	}
+	if json.Unmarshal(trimmed, &document) == nil && document.Projects != nil {
+		return "json"
+	}
+	return "ndjson"
+}
+
+// readJSONReport decodes a JSON report document
+func readJSONReport(data []byte) (*Report, error) {
+	var document struct {
+		Projects []*models.RepositoryStats `json:"projects"`
+		Metadata *models.ReportMetadata    `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid JSON report: %w", err)
+	}
+	return &Report{Stats: document.Projects, Metadata: document.Metadata}, nil
+}
+
+// readNDJSONReport decodes an NDJSON report, one project per line
+func readNDJSONReport(data []byte) (*Report, error) {
+	report := &Report{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var stat models.RepositoryStats
+		if err := json.Unmarshal(line, &stat); err != nil {
+			return nil, fmt.Errorf("invalid project on line %d: %w", lineNumber, err)
+		}
+		report.Stats = append(report.Stats, &stat)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// readCSVReport decodes a CSV report. Columns are matched by header name, so
+// reports written by older versions with fewer columns can be read too.
+func readCSVReport(data []byte) (*Report, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	header, err := reader.Read()
+	if err == io.EOF {
+		return &Report{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{"Namespace", "Project"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV report has no %s column", required)
+		}
+	}
+
+	report := &Report{}
+	for lineNumber := 2; ; lineNumber++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV row: %w", err)
+		}
+
+		stat, err := parseCSVRow(csvRow{columns: columns, record: record})
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV row on line %d: %w", lineNumber, err)
+		}
+		report.Stats = append(report.Stats, stat)
+	}
+	return report, nil
+}
+
+// parseCSVRow is the inverse of convertToCSVRow
+func parseCSVRow(row csvRow) (*models.RepositoryStats, error) {
+	stat := &models.RepositoryStats{
+		Namespace:         row.text("Namespace"),
+		RepoName:          row.text("Project"),
+		IsEmpty:           row.boolean("Is_Empty"),
+		IsFork:            row.boolean("isFork"),
+		IsArchive:         row.boolean("isArchive"),
+		HasWiki:           row.boolean("Has_Wiki"),
+		FullURL:           row.text("Full_URL"),
+		FailedMetrics:     row.list("Failed_Metrics"),
+		EstimatedMetrics:  row.list("Estimated_Metrics"),
+		TruncatedMetrics:  row.list("Truncated_Metrics"),
+		ProtectedBranches: parseProtectedBranches(row.text("Protected_Branches")),
+
+		MigrationComplexity: row.text("Migration_Complexity"),
+		MigrationWarnings:   row.list("Migration_Warnings"),
+	}
+
+	floats := map[string]*float64{
+		"Project_Size(mb)":      &stat.RepoSizeMB,
+		"LFS_Size(mb)":          &stat.LFSSizeMB,
+		"MR_Avg_Merge_Hours":    &stat.MRAvgMergeHours,
+		"MR_Median_Merge_Hours": &stat.MRMedianMergeHours,
+	}
+	for column, field := range floats {
+		value, err := row.float(column)
+		if err != nil {
+			return nil, err
+		}
+		*field = value
+	}
+
+	ints := map[string]*int{
+		"Project_ID":              &stat.ProjectID,
+		"Collaborator_Count":      &stat.CollaboratorCount,
+		"Protected_Branch_Count":  &stat.ProtectedBranchCount,
+		"MR_Review_Count":         &stat.MRReviewCount,
+		"Milestone_Count":         &stat.MilestoneCount,
+		"Issue_Count":             &stat.IssueCount,
+		"MR_Count":                &stat.MRCount,
+		"MR_Review_Comment_Count": &stat.MRReviewCommentCount,
+		"Commit_Count":            &stat.CommitCount,
+		"Issue_Comment_Count":     &stat.IssueCommentCount,
+		"Release_Count":           &stat.ReleaseCount,
+		"Branch_Count":            &stat.BranchCount,
+		"Tag_Count":               &stat.TagCount,
+		"MR_Open_Count":           &stat.MROpenCount,
+		"MR_Merged_Count":         &stat.MRMergedCount,
+		"MR_Closed_Count":         &stat.MRClosedCount,
+		"MR_Draft_Count":          &stat.MRDraftCount,
+		"MR_Discussion_Count":     &stat.MRDiscussionCount,
+		"MR_Diff_Note_Count":      &stat.MRDiffNoteCount,
+	}
+	for column, field := range ints {
+		value, err := row.integer(column)
+		if err != nil {
+			return nil, err
+		}
+		*field = value
+	}
+
+	times := map[string]**time.Time{
+		"Created":     &stat.Created,
+		"Last_Push":   &stat.LastPush,
+		"Last_Update": &stat.LastUpdate,
+	}
+	for column, field := range times {
+		value, err := row.time(column)
+		if err != nil {
+			return nil, err
+		}
+		*field = value
+	}
+
+	return stat, nil
+}
+
+// csvRow gives access to the cells of a CSV record by column name. Missing
+// columns and empty cells read as zero values.
+type csvRow struct {
+	columns map[string]int
+	record  []string
+}
+
+func (r csvRow) text(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return r.record[i]
+}
+
+func (r csvRow) boolean(column string) bool {
+	return r.text(column) == "true"
+}
+
+func (r csvRow) integer(column string) (int, error) {
+	cell := r.text(column)
+	if cell == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(cell)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", column, cell)
+	}
+	return value, nil
+}
+
+func (r csvRow) float(column string) (float64, error) {
+	cell := r.text(column)
+	if cell == "" {
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(cell, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", column, cell)
+	}
+	return value, nil
+}
+
+func (r csvRow) time(column string) (*time.Time, error) {
+	cell := r.text(column)
+	if cell == "" {
+		return nil, nil
+	}
+	value, err := time.Parse(time.RFC3339, cell)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q", column, cell)
+	}
+	return &value, nil
+}
+
+func (r csvRow) list(column string) []string {
+	cell := r.text(column)
+	if cell == "" {
+		return nil
+	}
+	return strings.Split(cell, ";")
+}
+
+// parseProtectedBranches is the inverse of formatProtectedBranches
+func parseProtectedBranches(cell string) []*models.ProtectedBranchRule {
+	if cell == "" {
+		return nil
+	}
+
+	var rules []*models.ProtectedBranchRule
+	for _, entry := range splitUnescaped(cell, '|') {
+		start := indexUnescaped(entry, '(')
+		if start < 0 || !strings.HasPrefix(entry[start:], "(push:") || !strings.HasSuffix(entry, ")") {
+			continue
+		}
+		rule := &models.ProtectedBranchRule{Name: unescapeProtectedBranch(entry[:start])}
+		for _, setting := range splitUnescaped(entry[start+1:len(entry)-1], ';') {
+			switch {
+			case strings.HasPrefix(setting, "push:"):
+				rule.PushAccess = splitAccess(strings.TrimPrefix(setting, "push:"))
+			case strings.HasPrefix(setting, "merge:"):
+				rule.MergeAccess = splitAccess(strings.TrimPrefix(setting, "merge:"))
+			case setting == "force-push":
+				rule.AllowForcePush = true
+			case setting == "code-owner-approval":
+				rule.CodeOwnerApprovalRequired = true
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// splitAccess splits a comma-separated access list, returning nil when it is empty
+func splitAccess(access string) []string {
+	if access == "" {
+		return nil
+	}
+	levels := splitUnescaped(access, ',')
+	for i, level := range levels {
+		levels[i] = unescapeProtectedBranch(level)
+	}
+	return levels
+}
+
+// splitUnescaped splits s around the occurrences of sep that aren't escaped with
+// a backslash. The parts keep their escapes.
+func splitUnescaped(s string, sep byte) []string {
+	var parts []string
+	for {
+		i := indexUnescaped(s, sep)
+		if i < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:i])
+		s = s[i+1:]
+	}
+}
+
+// indexUnescaped returns the index of the first occurrence of c in s that isn't
+// escaped with a backslash, or -1
+func indexUnescaped(s string, c byte) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case c:
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeProtectedBranch is the inverse of escapeProtectedBranch
+func unescapeProtectedBranch(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
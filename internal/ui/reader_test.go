@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+)
+
+func TestProtectedBranchesRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []*models.ProtectedBranchRule
+		cell  string
+	}{
+		{"none", nil, ""},
+		{
+			"plain",
+			[]*models.ProtectedBranchRule{
+				{Name: "main", PushAccess: []string{"Maintainers"}, MergeAccess: []string{"Developers + Maintainers"}},
+				{Name: "release/*", PushAccess: []string{"No one"}, MergeAccess: []string{"Maintainers"}, AllowForcePush: true, CodeOwnerApprovalRequired: true},
+			},
+			"main(push:Maintainers;merge:Developers + Maintainers)|release/*(push:No one;merge:Maintainers;force-push;code-owner-approval)",
+		},
+		{
+			"no access",
+			[]*models.ProtectedBranchRule{{Name: "main"}},
+			"main(push:;merge:)",
+		},
+		{
+			"separators in names",
+			[]*models.ProtectedBranchRule{
+				{Name: "feature|fix;v1,v2", PushAccess: []string{"Doe, Jane", "ops (on-call)"}, MergeAccess: []string{`back\slash`}},
+				{Name: "hotfix(push:x)", MergeAccess: []string{"a;b|c"}},
+			},
+			`feature\|fix\;v1\,v2(push:Doe\, Jane,ops \(on-call\);merge:back\\slash)|hotfix\(push:x\)(push:;merge:a\;b\|c)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cell := formatProtectedBranches(tt.rules)
+			if cell != tt.cell {
+				t.Errorf("formatProtectedBranches = %q, want %q", cell, tt.cell)
+			}
+			if got := parseProtectedBranches(cell); !reflect.DeepEqual(got, tt.rules) {
+				t.Errorf("parseProtectedBranches(%q) = %+v, want %+v", cell, got, tt.rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mona-actions/gh-gitlab-stats/internal/models"
+	"github.com/mona-actions/gh-gitlab-stats/internal/utils"
+)
+
+// WriteSummary writes namespace summaries as "csv", "json", "ndjson" or "table".
+// "-" writes to stdout.
+func WriteSummary(summaries []*models.NamespaceSummary, format, filename string) error {
+	file, err := CreateOutput(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", filename, err)
+	}
+
+	switch format {
+	case "csv":
+		err = writeSummaryCSV(file, summaries)
+	case "json":
+		err = writeSummaryJSON(file, summaries)
+	case "ndjson":
+		err = writeSummaryNDJSON(file, summaries)
+	case "table":
+		writeSummaryTable(file, summaries)
+	default:
+		err = fmt.Errorf("unsupported summary format: %s (supported: 'csv', 'json', 'ndjson', 'table')", format)
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// getSummaryCSVHeaders returns the summary CSV header row
+func getSummaryCSVHeaders() []string {
+	return []string{
+		"Level",
+		"Namespace",
+		"Project_Count",
+		"Archived_Count",
+		"Empty_Count",
+		"Fork_Count",
+		"Total_Size(mb)",
+		"Avg_Size(mb)",
+		"Max_Size(mb)",
+		"Total_LFS_Size(mb)",
+		"Avg_LFS_Size(mb)",
+		"Max_LFS_Size(mb)",
+		"Issue_Count",
+		"MR_Count",
+		"Commit_Count",
+		"Last_Activity",
+		"Last_Active_Project",
+	}
+}
+
+// writeSummaryCSV writes one CSV row per summary
+func writeSummaryCSV(w io.Writer, summaries []*models.NamespaceSummary) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(getSummaryCSVHeaders()); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, summary := range summaries {
+		row := []string{
+			summary.Level,
+			summary.Namespace,
+			fmt.Sprintf("%d", summary.ProjectCount),
+			fmt.Sprintf("%d", summary.ArchivedCount),
+			fmt.Sprintf("%d", summary.EmptyCount),
+			fmt.Sprintf("%d", summary.ForkCount),
+			fmt.Sprintf("%.0f", summary.TotalSizeMB),
+			fmt.Sprintf("%.1f", summary.AvgSizeMB),
+			fmt.Sprintf("%.0f", summary.MaxSizeMB),
+			fmt.Sprintf("%.0f", summary.TotalLFSSizeMB),
+			fmt.Sprintf("%.1f", summary.AvgLFSSizeMB),
+			fmt.Sprintf("%.0f", summary.MaxLFSSizeMB),
+			fmt.Sprintf("%d", summary.IssueCount),
+			fmt.Sprintf("%d", summary.MRCount),
+			fmt.Sprintf("%d", summary.CommitCount),
+			timeToString(summary.LastActivity),
+			summary.LastActiveProject,
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush CSV output: %w", err)
+	}
+	return nil
+}
+
+// writeSummaryJSON writes the summaries as a JSON document with a "summary" array,
+// laid out like the JSON report
+func writeSummaryJSON(w io.Writer, summaries []*models.NamespaceSummary) error {
+	var document strings.Builder
+	document.WriteString("{\n  \"summary\": [")
+	for i, summary := range summaries {
+		data, err := marshalJSON(summary, "    ")
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			document.WriteString(",")
+		}
+		document.WriteString("\n    " + string(data))
+	}
+	if len(summaries) > 0 {
+		document.WriteString("\n  ")
+	}
+	document.WriteString("]\n}\n")
+
+	if _, err := io.WriteString(w, document.String()); err != nil {
+		return fmt.Errorf("failed to write JSON output: %w", err)
+	}
+	return nil
+}
+
+// writeSummaryNDJSON writes one summary per line
+func writeSummaryNDJSON(w io.Writer, summaries []*models.NamespaceSummary) error {
+	for _, summary := range summaries {
+		data, err := marshalJSON(summary, "")
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("failed to write NDJSON output: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeSummaryTable renders the summaries as a console table, indenting the
+// namespaces under their group
+func writeSummaryTable(w io.Writer, summaries []*models.NamespaceSummary) {
+	if len(summaries) <= 1 {
+		fmt.Fprintln(w, "No repositories found.")
+		return
+	}
+
+	fmt.Fprintf(w, "%-40s %8s %8s %6s %6s %12s %10s %10s %10s %8s %8s %10s  %-10s %s\n",
+		"Namespace", "Projects", "Archived", "Empty", "Forks", "Size(MB)", "Avg(MB)", "Max(MB)", "LFS(MB)",
+		"Issues", "MRs", "Commits", "Active", "Most Recent Project")
+	fmt.Fprintln(w, strings.Repeat("-", 175))
+
+	for _, summary := range summaries {
+		name := summary.Namespace
+		switch summary.Level {
+		case models.SummaryNamespace:
+			name = "  " + name
+		case models.SummaryTotal:
+			fmt.Fprintln(w, strings.Repeat("-", 175))
+			name = "Total"
+		}
+
+		active := ""
+		if summary.LastActivity != nil {
+			active = summary.LastActivity.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%-40s %8d %8d %6d %6d %12.0f %10.1f %10.0f %10.0f %8d %8d %10d  %-10s %s\n",
+			utils.Truncate(name, 40),
+			summary.ProjectCount,
+			summary.ArchivedCount,
+			summary.EmptyCount,
+			summary.ForkCount,
+			summary.TotalSizeMB,
+			summary.AvgSizeMB,
+			summary.MaxSizeMB,
+			summary.TotalLFSSizeMB,
+			summary.IssueCount,
+			summary.MRCount,
+			summary.CommitCount,
+			active,
+			summary.LastActiveProject)
+	}
+}
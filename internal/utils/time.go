@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseAge parses a duration given in days ("365d"), weeks ("12w"), years ("1y",
+// counted as 365 days) or any unit accepted by time.ParseDuration ("36h")
+func ParseAge(s string) (time.Duration, error) {
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+		"y": 365 * 24 * time.Hour,
+	}
+	for suffix, unit := range units {
+		if number, ok := strings.CutSuffix(s, suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 365d, 12w, 1y or 36h)", s)
+	}
+	return d, nil
+}
+
+// ParseDate parses a date ("2025-01-01", midnight UTC) or an RFC 3339 timestamp
+func ParseDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD or RFC 3339)", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"365d", 365 * day, false},
+		{"0d", 0, false},
+		{"12w", 84 * day, false},
+		{"1y", 365 * day, false},
+		{"2y", 730 * day, false},
+		{"36h", 36 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"", 0, true},
+		{"d", 0, true},
+		{"1.5d", 0, true},
+		{"-3d", 0, true},
+		{"-36h", 0, true},
+		{"10", 0, true},
+		{"ten days", 0, true},
+		{"3mo", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseAge(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAge(%q) error = %v, want error %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAge(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2025-01-01", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2025-01-01T12:30:00Z", time.Date(2025, 1, 1, 12, 30, 0, 0, time.UTC), false},
+		{"2025-01-01T12:30:00+02:00", time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC), false},
+		{"01/01/2025", time.Time{}, true},
+		{"2025-13-01", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDate(%q) error = %v, want error %v", tt.input, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDate(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}